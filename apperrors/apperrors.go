@@ -0,0 +1,71 @@
+package apperrors
+
+import (
+	"errors"
+	"my_blog/models"
+)
+
+// Kind 错误类别，决定返回给客户端的HTTP状态码
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindForbidden
+	KindUnauthorized
+)
+
+// Error 服务层返回的领域错误。Code 为 i18n 消息码，按请求语言展示给客户端；Err 为内部原因，只写入日志
+type Error struct {
+	Kind   Kind
+	Code   string
+	Fields []models.FieldError
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Code + ": " + e.Err.Error()
+	}
+	return e.Code
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NotFound 资源不存在
+func NotFound(code string) *Error {
+	return &Error{Kind: KindNotFound, Code: code}
+}
+
+// Conflict 资源冲突，如用户名已存在
+func Conflict(code string) *Error {
+	return &Error{Kind: KindConflict, Code: code}
+}
+
+// Validation 请求数据不合法，fields 可列出具体字段
+func Validation(code string, fields ...models.FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: code, Fields: fields}
+}
+
+// Forbidden 已登录但无权操作
+func Forbidden(code string) *Error {
+	return &Error{Kind: KindForbidden, Code: code}
+}
+
+// Unauthorized 未登录或认证失败
+func Unauthorized(code string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code}
+}
+
+// As 提取错误链中的领域错误，不是领域错误时返回nil
+func As(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return nil
+}
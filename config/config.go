@@ -2,7 +2,17 @@ package config
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
+	"log/slog"
+	"my_blog/events"
+	"my_blog/logging"
+	"my_blog/mailer"
+	"my_blog/ratelimit"
+	"my_blog/realtime"
+	"my_blog/storage"
+	"os"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -10,8 +20,107 @@ import (
 var (
 	DB        *sql.DB
 	JWTSecret = "your-secret-key-here"
+
+	// Logger 全局结构化日志记录器，由 InitLogger 根据环境变量配置
+	Logger = slog.Default()
+
+	// Storage 上传文件存储后端，由 InitStorage 根据环境变量选择
+	Storage storage.Storage
+
+	// UploadDir 本地存储后端的上传目录，对外通过 UploadURLPrefix 访问
+	UploadDir       = "uploads"
+	UploadURLPrefix = "/uploads/"
+
+	// UploadGCInterval 未引用上传文件的清理周期
+	UploadGCInterval = 6 * time.Hour
+	// UploadGCGracePeriod 新上传文件在此时间内即使未被引用也不会被清理
+	UploadGCGracePeriod = 24 * time.Hour
+
+	// TrashRetention 删除的文章和评论在回收站中的保留时间，可通过 TRASH_RETENTION 设置（如 720h），
+	// 超过后由每 TrashPurgeInterval 执行一次的清理任务永久删除
+	TrashRetention     = getEnvDuration("TRASH_RETENTION", 30*24*time.Hour)
+	TrashPurgeInterval = time.Hour
+
+	// ServerAddr HTTP服务监听地址
+	ServerAddr = ":8080"
+	// HTTP服务器超时设置，防止慢连接长期占用资源
+	ServerReadHeaderTimeout = 5 * time.Second
+	ServerReadTimeout       = 30 * time.Second
+	ServerWriteTimeout      = 60 * time.Second
+	ServerIdleTimeout       = 120 * time.Second
+	// ShutdownDrainDelay 收到退出信号后 /readyz 先返回失败，经过此时间（留给负载均衡摘除实例）才关闭监听，
+	// 可通过 SHUTDOWN_DRAIN_DELAY 设置，应大于就绪检查的间隔
+	ShutdownDrainDelay = getEnvDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second)
+	// ShutdownTimeout 关闭监听后等待进行中请求和后台任务完成的最长时间
+	ShutdownTimeout = 30 * time.Second
+
+	// Mailer 邮件发送器，由 InitMailer 根据环境变量选择
+	Mailer mailer.Mailer
+	// AppBaseURL 前端地址，用于生成邮件中的验证和重置链接
+	AppBaseURL = getEnv("APP_BASE_URL", "http://localhost:8081")
+	// EmailVerificationTTL 邮箱验证链接有效期，PasswordResetTTL 密码重置链接有效期
+	EmailVerificationTTL = 24 * time.Hour
+	PasswordResetTTL     = time.Hour
+
+	// MailQueueInterval 邮件队列的处理周期，每次最多发送 MailBatchSize 封
+	MailQueueInterval = 10 * time.Second
+	MailBatchSize     = 20
+	// MailMaxAttempts 邮件发送的最大尝试次数，失败后的重试间隔从 MailRetryBackoff 开始倍增
+	MailMaxAttempts  = 8
+	MailRetryBackoff = time.Minute
+	// MailClaimTimeout 邮件被领取后未完成发送（如进程退出）时，超过该时间会被重新发送
+	MailClaimTimeout = 5 * time.Minute
+
+	// RateLimitStore 限流令牌桶存储，默认进程内存储；多实例部署时可替换为共享存储实现
+	RateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	// 各路由的限流设置：登录和注册按IP，发表评论按用户
+	LoginRateLimit    = ratelimit.PerMinute(10, 5)
+	RegisterRateLimit = ratelimit.PerHour(10, 3)
+	CommentRateLimit  = ratelimit.PerMinute(6, 3)
+	// MailRateLimit 会发送邮件的接口（找回密码、重发验证邮件）的限流，防止滥发邮件
+	MailRateLimit = ratelimit.PerHour(5, 3)
+	// TrustProxyHeaders 部署在反向代理之后时设置 TRUST_PROXY_HEADERS=true，按 X-Forwarded-For 识别客户端IP
+	TrustProxyHeaders = os.Getenv("TRUST_PROXY_HEADERS") == "true"
+
+	// Events 领域事件总线，业务操作完成后发布事件，由实时推送、Webhook 等订阅
+	Events = events.NewBus()
+
+	// WebhookInterval Webhook 投递队列的处理周期，每次最多投递 WebhookBatchSize 个
+	WebhookInterval  = 5 * time.Second
+	WebhookBatchSize = 20
+	// WebhookTimeout 单次投递的请求超时
+	WebhookTimeout = 10 * time.Second
+	// WebhookMaxAttempts 投递的最大尝试次数，失败后的重试间隔从 WebhookRetryBackoff 开始倍增
+	WebhookMaxAttempts  = 8
+	WebhookRetryBackoff = 30 * time.Second
+	// WebhookClaimTimeout 投递被领取后未完成（如进程退出）时，超过该时间会被重新投递
+	WebhookClaimTimeout = 2 * time.Minute
+	// WebhookDeliveryRetention 已完成（成功或最终失败）的投递记录的保留时间，可通过 WEBHOOK_DELIVERY_RETENTION 设置，
+	// 超过后由每 WebhookPruneInterval 执行一次的清理任务删除
+	WebhookDeliveryRetention = getEnvDuration("WEBHOOK_DELIVERY_RETENTION", 30*24*time.Hour)
+	WebhookPruneInterval     = time.Hour
+
+	// Realtime 实时推送的发布/订阅中心，StreamHeartbeatInterval 为推送长连接的心跳间隔，防止代理断开空闲连接
+	Realtime                = realtime.NewHub()
+	StreamHeartbeatInterval = 25 * time.Second
+
+	// MaxFailedLogins 连续登录失败达到该次数后锁定账号 LoginLockoutDuration
+	MaxFailedLogins      = 5
+	LoginLockoutDuration = 15 * time.Minute
+
+	// DBConnectAttempts 启动时连接数据库的最大尝试次数，间隔从 DBConnectBackoff 开始倍增，最长30秒
+	DBConnectAttempts = 10
+	DBConnectBackoff  = time.Second
 )
 
+// InitLogger 初始化日志记录器
+// LOG_LEVEL 取 debug、info（默认）、warn、error；LOG_FORMAT=json 时输出JSON，默认为文本格式。
+// 同时设为 slog 和标准库 log 的默认输出
+func InitLogger() {
+	Logger = logging.New(os.Stdout, getEnv("LOG_LEVEL", "info"), getEnv("LOG_FORMAT", "text"))
+	slog.SetDefault(Logger)
+}
+
 // InitDB 初始化数据库连接
 func InitDB() {
 	var err error
@@ -20,13 +129,89 @@ func InitDB() {
 		log.Fatal(err)
 	}
 
-	if err = DB.Ping(); err != nil {
-		log.Fatal(err)
+	// 数据库可能晚于应用启动（如容器编排），连接失败时退避重试
+	backoff := DBConnectBackoff
+	for attempt := 1; ; attempt++ {
+		if err = DB.Ping(); err == nil {
+			break
+		}
+		if attempt >= DBConnectAttempts {
+			log.Fatal("连接数据库失败:", err)
+		}
+		Logger.Warn("database not ready, retrying", "attempt", attempt, "retry_in", backoff, "error", err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, 30*time.Second)
 	}
 
 	createTables()
 }
 
+// InitStorage 初始化上传文件存储后端
+// STORAGE_BACKEND=local（默认）保存在 UploadDir；STORAGE_BACKEND=s3 使用S3兼容存储，
+// 多个实例可共享同一个bucket
+func InitStorage() {
+	var err error
+	Storage, err = storage.New(storage.Config{
+		Backend:    os.Getenv("STORAGE_BACKEND"),
+		LocalDir:   UploadDir,
+		URLPrefix:  UploadURLPrefix,
+		SigningKey: getEnv("STORAGE_SIGNING_KEY", JWTSecret),
+		S3: storage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PathStyle: os.Getenv("S3_PATH_STYLE") == "true",
+		},
+	})
+	if err != nil {
+		log.Fatal("初始化存储失败:", err)
+	}
+}
+
+// InitMailer 初始化邮件发送器
+// MAIL_BACKEND=file（默认）将邮件保存到 MAIL_DIR 目录供开发调试；MAIL_BACKEND=smtp 通过
+// SMTP_HOST、SMTP_PORT、SMTP_USERNAME、SMTP_PASSWORD 配置的服务器发送，
+// SMTP_SECURITY=none 时不加密，可指向本地邮件捕获工具测试
+func InitMailer() {
+	var err error
+	Mailer, err = mailer.New(mailer.Config{
+		Backend:      os.Getenv("MAIL_BACKEND"),
+		From:         getEnv("MAIL_FROM", "no-reply@localhost"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     os.Getenv("SMTP_PORT"),
+		SMTPSecurity: os.Getenv("SMTP_SECURITY"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		Dir:          getEnv("MAIL_DIR", "mail"),
+	})
+	if err != nil {
+		log.Fatal("初始化邮件发送失败:", err)
+	}
+}
+
+// getEnv 读取环境变量，未设置时返回默认值
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// getEnvDuration 读取时长类型的环境变量，未设置时返回默认值
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Fatalf("环境变量 %s 不是有效的时长: %q", key, v)
+	}
+	return d
+}
+
 // createTables 创建数据库表
 func createTables() {
 	// 创建分类表
@@ -82,6 +267,215 @@ func createTables() {
 		log.Fatal("创建comments表失败:", err)
 	}
 
+	// 创建文章和评论的反应表，每个用户对同一内容的每种反应只能有一个
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS article_reactions (
+		article_id INT NOT NULL,
+		user_id INT NOT NULL,
+		reaction VARCHAR(16) NOT NULL,
+		created_at DATETIME NOT NULL,
+		PRIMARY KEY (article_id, user_id, reaction),
+		INDEX idx_user (user_id),
+		FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		log.Fatal("创建article_reactions表失败:", err)
+	}
+
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS comment_reactions (
+		comment_id INT NOT NULL,
+		user_id INT NOT NULL,
+		reaction VARCHAR(16) NOT NULL,
+		created_at DATETIME NOT NULL,
+		PRIMARY KEY (comment_id, user_id, reaction),
+		INDEX idx_user (user_id),
+		FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		log.Fatal("创建comment_reactions表失败:", err)
+	}
+
+	// 创建关注表：用户关注用户、用户关注分类
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS user_follows (
+		follower_id INT NOT NULL,
+		followee_id INT NOT NULL,
+		created_at DATETIME NOT NULL,
+		PRIMARY KEY (follower_id, followee_id),
+		INDEX idx_followee (followee_id),
+		FOREIGN KEY (follower_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (followee_id) REFERENCES users(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		log.Fatal("创建user_follows表失败:", err)
+	}
+
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS category_follows (
+		user_id INT NOT NULL,
+		category_id INT NOT NULL,
+		created_at DATETIME NOT NULL,
+		PRIMARY KEY (user_id, category_id),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		log.Fatal("创建category_follows表失败:", err)
+	}
+
+	// 创建收藏表
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS bookmarks (
+		user_id INT NOT NULL,
+		article_id INT NOT NULL,
+		created_at DATETIME NOT NULL,
+		PRIMARY KEY (user_id, article_id),
+		INDEX idx_user_created (user_id, created_at),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		log.Fatal("创建bookmarks表失败:", err)
+	}
+
+	// 创建阅读列表表，同一用户的列表名称不能重复
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS reading_lists (
+		id INT PRIMARY KEY AUTO_INCREMENT,
+		user_id INT NOT NULL,
+		name VARCHAR(100) NOT NULL,
+		description TEXT,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		UNIQUE KEY uk_user_name (user_id, name),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		log.Fatal("创建reading_lists表失败:", err)
+	}
+
+	// 创建阅读列表条目表，position 为列表内的排序
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS reading_list_items (
+		list_id INT NOT NULL,
+		article_id INT NOT NULL,
+		position INT NOT NULL,
+		added_at DATETIME NOT NULL,
+		PRIMARY KEY (list_id, article_id),
+		INDEX idx_list_position (list_id, position),
+		FOREIGN KEY (list_id) REFERENCES reading_lists(id) ON DELETE CASCADE,
+		FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		log.Fatal("创建reading_list_items表失败:", err)
+	}
+
+	// 创建上传文件表（按内容哈希去重，记录引用计数）
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS uploads (
+		hash CHAR(64) PRIMARY KEY,
+		path VARCHAR(255) NOT NULL UNIQUE,
+		ref_count INT NOT NULL DEFAULT 0,
+		created_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		log.Fatal("创建uploads表失败:", err)
+	}
+
+	// 创建用户令牌表（邮箱验证、密码重置），只保存令牌的SHA-256哈希
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS user_tokens (
+		id INT PRIMARY KEY AUTO_INCREMENT,
+		user_id INT NOT NULL,
+		purpose VARCHAR(32) NOT NULL,
+		token_hash CHAR(64) NOT NULL UNIQUE,
+		expires_at DATETIME NOT NULL,
+		used_at DATETIME DEFAULT NULL,
+		created_at DATETIME NOT NULL,
+		INDEX idx_user_purpose (user_id, purpose),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		log.Fatal("创建user_tokens表失败:", err)
+	}
+
+	// 创建邮件发送队列表
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS email_queue (
+		id BIGINT PRIMARY KEY AUTO_INCREMENT,
+		recipient VARCHAR(255) NOT NULL,
+		template VARCHAR(64) NOT NULL,
+		subject TEXT NOT NULL,
+		body TEXT NOT NULL,
+		status VARCHAR(16) NOT NULL DEFAULT 'pending',
+		attempts INT NOT NULL DEFAULT 0,
+		last_error TEXT,
+		next_attempt_at DATETIME NOT NULL,
+		created_at DATETIME NOT NULL,
+		sent_at DATETIME DEFAULT NULL,
+		INDEX idx_status_next (status, next_attempt_at)
+	)`)
+	if err != nil {
+		log.Fatal("创建email_queue表失败:", err)
+	}
+
+	// 创建通知偏好表，没有记录的用户使用默认设置（全部开启）
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS notification_preferences (
+		user_id INT PRIMARY KEY,
+		email_comment BOOLEAN NOT NULL DEFAULT TRUE,
+		email_reply BOOLEAN NOT NULL DEFAULT TRUE,
+		email_moderation BOOLEAN NOT NULL DEFAULT TRUE,
+		email_account BOOLEAN NOT NULL DEFAULT TRUE,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		log.Fatal("创建notification_preferences表失败:", err)
+	}
+
+	// 创建站内通知表
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS notifications (
+		id BIGINT PRIMARY KEY AUTO_INCREMENT,
+		user_id INT NOT NULL,
+		type VARCHAR(32) NOT NULL,
+		article_id INT DEFAULT NULL,
+		comment_id INT DEFAULT NULL,
+		data TEXT,
+		read_at DATETIME DEFAULT NULL,
+		create_at DATETIME NOT NULL,
+		INDEX idx_user_created (user_id, create_at),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		log.Fatal("创建notifications表失败:", err)
+	}
+
+	// 创建 Webhook 订阅表，events 为逗号分隔的事件类型
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS webhooks (
+		id INT PRIMARY KEY AUTO_INCREMENT,
+		url VARCHAR(512) NOT NULL,
+		secret VARCHAR(128) NOT NULL,
+		events VARCHAR(512) NOT NULL,
+		active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		log.Fatal("创建webhooks表失败:", err)
+	}
+
+	// 创建 Webhook 投递记录表，同时作为投递队列
+	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id BIGINT PRIMARY KEY AUTO_INCREMENT,
+		webhook_id INT NOT NULL,
+		event VARCHAR(64) NOT NULL,
+		payload MEDIUMTEXT NOT NULL,
+		status VARCHAR(16) NOT NULL DEFAULT 'pending',
+		attempts INT NOT NULL DEFAULT 0,
+		response_status INT DEFAULT NULL,
+		last_error TEXT,
+		next_attempt_at DATETIME NOT NULL,
+		created_at DATETIME NOT NULL,
+		delivered_at DATETIME DEFAULT NULL,
+		INDEX idx_status_next (status, next_attempt_at),
+		INDEX idx_webhook_created (webhook_id, created_at),
+		FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		log.Fatal("创建webhook_deliveries表失败:", err)
+	}
+
 	// 创建角色表
 	_, err = DB.Exec(`CREATE TABLE IF NOT EXISTS roles (
 		id INT PRIMARY KEY AUTO_INCREMENT,
@@ -99,4 +493,78 @@ func createTables() {
 	if err != nil {
 		log.Fatal("插入默认角色失败:", err)
 	}
+
+	// 为已存在的表补充后续新增的列
+	addColumn("users", "locale", "VARCHAR(10) DEFAULT NULL")              // 偏好语言
+	addColumn("users", "failed_logins", "INT NOT NULL DEFAULT 0")         // 连续登录失败次数
+	addColumn("users", "locked_until", "DATETIME DEFAULT NULL")           // 账号锁定截止时间
+	addColumn("users", "email_verified_at", "DATETIME DEFAULT NULL")      // 邮箱验证时间
+	addColumn("users", "bio", "VARCHAR(500) DEFAULT NULL")                // 个人简介
+	addColumn("users", "website", "VARCHAR(255) DEFAULT NULL")            // 个人网站
+	addColumn("users", "location", "VARCHAR(100) DEFAULT NULL")           // 所在地
+	addColumn("users", "social_links", "TEXT DEFAULT NULL")               // 社交账号链接（JSON对象）
+	addColumn("users", "status", "VARCHAR(16) NOT NULL DEFAULT 'active'") // 账号状态
+	addColumn("users", "status_reason", "VARCHAR(500) DEFAULT NULL")      // 暂停或封禁的原因
+	addColumn("users", "status_until", "DATETIME DEFAULT NULL")           // 暂停截止时间，为空表示直到解除
+	addColumn("users", "deleted_at", "DATETIME DEFAULT NULL")             // 注销时间
+	addColumn("articles", "deleted_at", "DATETIME DEFAULT NULL")          // 移入回收站的时间
+	addColumn("articles", "deleted_by", "INT DEFAULT NULL")               // 执行删除的用户
+	addColumn("comments", "user_id", "INT DEFAULT NULL")                  // 发表评论的用户
+	addColumn("comments", "parent_id", "INT DEFAULT NULL")                // 回复的评论
+	addColumn("comments", "deleted_at", "DATETIME DEFAULT NULL")          // 移入回收站的时间
+	addColumn("comments", "deleted_by", "INT DEFAULT NULL")               // 执行删除的用户
+	addColumn("comments", "updated_at", "DATETIME DEFAULT NULL")          // 最后编辑时间
+
+	// 发布文章的用户。旧文章只以用户名保存作者，新增该列时按当时的用户名补充
+	if addColumn("articles", "author_id", "INT DEFAULT NULL") {
+		_, err = DB.Exec("UPDATE articles a JOIN users u ON u.username = a.author SET a.author_id = u.id")
+		if err != nil {
+			log.Fatal("补充articles.author_id失败:", err)
+		}
+	}
+
+	// 为已存在的表补充后续新增的索引
+	addIndex("webhook_deliveries", "idx_created", "created_at") // 清理过期的投递记录
+	addIndex("articles", "idx_deleted_at", "deleted_at")        // 回收站列表和清理
+	addIndex("comments", "idx_deleted_at", "deleted_at")        // 回收站列表和清理
+	addIndex("articles", "idx_author_id", "author_id")          // 作者主页和关注动态
+}
+
+// addColumn 列不存在时为表添加新列（CREATE TABLE IF NOT EXISTS 不会修改已存在的表），返回是否新增了该列
+func addColumn(table, column, definition string) bool {
+	var count int
+	err := DB.QueryRow(`
+		SELECT COUNT(*) FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?
+	`, table, column).Scan(&count)
+	if err != nil {
+		log.Fatalf("检查%s.%s列失败: %v", table, column, err)
+	}
+	if count > 0 {
+		return false
+	}
+
+	if _, err := DB.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		log.Fatalf("添加%s.%s列失败: %v", table, column, err)
+	}
+	return true
+}
+
+// addIndex 索引不存在时为表添加索引
+func addIndex(table, index, columns string) {
+	var count int
+	err := DB.QueryRow(`
+		SELECT COUNT(*) FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND INDEX_NAME = ?
+	`, table, index).Scan(&count)
+	if err != nil {
+		log.Fatalf("检查%s.%s索引失败: %v", table, index, err)
+	}
+	if count > 0 {
+		return
+	}
+
+	if _, err := DB.Exec(fmt.Sprintf("ALTER TABLE %s ADD INDEX %s (%s)", table, index, columns)); err != nil {
+		log.Fatalf("添加%s.%s索引失败: %v", table, index, err)
+	}
 }
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"my_blog/i18n"
+	"my_blog/middleware"
+	"my_blog/services"
+	"my_blog/utils"
+	"net/http"
+)
+
+type AccountController struct {
+	accountService *services.AccountService
+}
+
+func NewAccountController(logger *slog.Logger) *AccountController {
+	return &AccountController{
+		accountService: services.NewAccountService(logger),
+	}
+}
+
+// VerifyEmail 使用邮件中的令牌验证邮箱
+func (c *AccountController) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token string `json:"token" validate:"required,max=128"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
+		return
+	}
+
+	if err := c.accountService.VerifyEmail(req.Token); err != nil {
+		utils.SendError(w, r, err, i18n.MsgVerifyEmailFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgEmailVerified, nil)
+}
+
+// ResendVerification 重新发送当前用户的邮箱验证邮件
+func (c *AccountController) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	if err := c.accountService.SendVerificationEmail(userID); err != nil {
+		utils.SendError(w, r, err, i18n.MsgSendVerificationFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgVerificationSent, nil)
+}
+
+// ForgotPassword 申请重置密码，无论邮箱是否注册都返回相同响应
+func (c *AccountController) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email string `json:"email" validate:"required,email,max=255"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
+		return
+	}
+
+	c.accountService.ForgotPassword(req.Email)
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgPasswordResetRequested, nil)
+}
+
+// ResetPassword 使用邮件中的令牌设置新密码
+func (c *AccountController) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token    string `json:"token" validate:"required,max=128"`
+		Password string `json:"password" validate:"required,password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
+		return
+	}
+
+	if err := c.accountService.ResetPassword(req.Token, req.Password); err != nil {
+		utils.SendError(w, r, err, i18n.MsgResetPasswordFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgPasswordReset, nil)
+}
+
+// ChangePassword 当前用户修改密码
+func (c *AccountController) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	var req struct {
+		OldPassword string `json:"old_password" validate:"required"`
+		NewPassword string `json:"new_password" validate:"required,password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
+		return
+	}
+
+	if err := c.accountService.ChangePassword(userID, req.OldPassword, req.NewPassword); err != nil {
+		utils.SendError(w, r, err, i18n.MsgChangePasswordFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgPasswordChanged, nil)
+}
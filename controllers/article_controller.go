@@ -2,7 +2,11 @@ package controllers
 
 import (
 	"encoding/json"
+	"log/slog"
+	"my_blog/i18n"
+	"my_blog/middleware"
 	"my_blog/models"
+	"my_blog/serializers"
 	"my_blog/services"
 	"my_blog/utils"
 	"net/http"
@@ -11,13 +15,52 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// createArticleRequest 创建文章请求数据，作者为当前登录用户
+type createArticleRequest struct {
+	Title        string  `json:"title" validate:"required,max=255"`
+	Content      string  `json:"content" validate:"required,maxbytes=65535"`
+	ImagePath    *string `json:"image_path,omitempty" validate:"omitempty,max=255"`
+	CategoryName string  `json:"category_name" validate:"required,max=255"`
+}
+
+// updateArticleRequest PUT 更新文章请求数据，标题和内容必填；未提供 image_path 时保留原配图，为 null 时移除
+type updateArticleRequest struct {
+	Title     string                  `json:"title" validate:"required,max=255"`
+	Content   string                  `json:"content" validate:"required,maxbytes=65535"`
+	ImagePath models.Optional[string] `json:"image_path" validate:"omitempty,max=255"`
+}
+
+func (req *updateArticleRequest) toPatch() models.ArticlePatch {
+	return models.ArticlePatch{
+		Title:     models.Some(req.Title),
+		Content:   models.Some(req.Content),
+		ImagePath: req.ImagePath,
+	}
+}
+
+// patchArticleRequest PATCH 部分更新文章请求数据（JSON Merge Patch），只修改出现的字段
+type patchArticleRequest struct {
+	Title     models.Optional[string] `json:"title" validate:"required,max=255"`
+	Content   models.Optional[string] `json:"content" validate:"required,maxbytes=65535"`
+	ImagePath models.Optional[string] `json:"image_path" validate:"omitempty,max=255"`
+}
+
+func (req *patchArticleRequest) toPatch() models.ArticlePatch {
+	return models.ArticlePatch{Title: req.Title, Content: req.Content, ImagePath: req.ImagePath}
+}
+
+// articlePatchRequest 可转换为文章部分更新的请求数据
+type articlePatchRequest interface {
+	toPatch() models.ArticlePatch
+}
+
 type ArticleController struct {
 	articleService *services.ArticleService
 }
 
-func NewArticleController() *ArticleController {
+func NewArticleController(logger *slog.Logger) *ArticleController {
 	return &ArticleController{
-		articleService: &services.ArticleService{},
+		articleService: services.NewArticleService(logger),
 	}
 }
 
@@ -25,157 +68,166 @@ func NewArticleController() *ArticleController {
 func (c *ArticleController) GetArticles(w http.ResponseWriter, r *http.Request) {
 	// 检查服务是否初始化
 	if c.articleService == nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "服务未初始化")
+		utils.SendErrorResponse(w, r, http.StatusInternalServerError, i18n.MsgServiceUnavailable)
 		return
 	}
 
-	articles, err := c.articleService.GetAllArticles()
+	userID, _ := middleware.UserIDFromContext(r.Context())
+	articles, err := c.articleService.GetAllArticles(userID)
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "获取文章列表失败")
+		utils.SendError(w, r, err, i18n.MsgGetArticlesFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "成功", articles)
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.Articles(articles))
 }
 
 // GetArticle 获取单个文章
 func (c *ArticleController) GetArticle(w http.ResponseWriter, r *http.Request) {
 	if c.articleService == nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "服务未初始化")
+		utils.SendErrorResponse(w, r, http.StatusInternalServerError, i18n.MsgServiceUnavailable)
 		return
 	}
 
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的文章ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidArticleID)
 		return
 	}
 
-	article, err := c.articleService.GetArticleByID(id)
+	userID, _ := middleware.UserIDFromContext(r.Context())
+	article, err := c.articleService.GetArticleByID(id, userID)
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "获取文章失败")
-		return
-	}
-
-	if article == nil {
-		utils.SendErrorResponse(w, http.StatusNotFound, "文章不存在")
+		utils.SendError(w, r, err, i18n.MsgGetArticleFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "成功", article)
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.Article(article))
 }
 
 // CreateArticle 创建文章
 func (c *ArticleController) CreateArticle(w http.ResponseWriter, r *http.Request) {
 	if c.articleService == nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "服务未初始化")
+		utils.SendErrorResponse(w, r, http.StatusInternalServerError, i18n.MsgServiceUnavailable)
 		return
 	}
 
-	var req struct {
-		Title        string  `json:"title"`
-		Content      string  `json:"content"`
-		Author       string  `json:"author"`
-		ImagePath    *string `json:"image_path,omitempty"`
-		CategoryName string  `json:"category_name"`
-	}
-
+	var req createArticleRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的请求数据")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
 		return
 	}
 
-	if req.Title == "" || req.Content == "" || req.Author == "" || req.CategoryName == "" {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "标题、内容、作者和分类名不能为空")
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
 		return
 	}
 
+	userID, _ := middleware.UserIDFromContext(r.Context())
 	article := &models.Article{
 		Title:     req.Title,
 		Content:   req.Content,
-		Author:    req.Author,
+		AuthorID:  userID,
 		ImagePath: req.ImagePath,
 	}
 
 	id, err := c.articleService.CreateArticle(article, req.CategoryName)
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "创建文章失败: "+err.Error())
+		utils.SendError(w, r, err, i18n.MsgCreateArticleFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusCreated, "文章创建成功", map[string]interface{}{"id": id})
+	utils.SendResponse(w, r, http.StatusCreated, i18n.MsgArticleCreated, map[string]interface{}{"id": id})
 }
 
-// UpdateArticle 更新文章
+// UpdateArticle 更新文章，未提供配图时保留原配图
 func (c *ArticleController) UpdateArticle(w http.ResponseWriter, r *http.Request) {
+	c.updateArticle(w, r, &updateArticleRequest{})
+}
+
+// PatchArticle 部分更新文章，只修改请求中出现的字段
+func (c *ArticleController) PatchArticle(w http.ResponseWriter, r *http.Request) {
+	c.updateArticle(w, r, &patchArticleRequest{})
+}
+
+// updateArticle 解析请求数据到 req 并按其生成的补丁更新文章
+func (c *ArticleController) updateArticle(w http.ResponseWriter, r *http.Request, req articlePatchRequest) {
 	if c.articleService == nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "服务未初始化")
+		utils.SendErrorResponse(w, r, http.StatusInternalServerError, i18n.MsgServiceUnavailable)
 		return
 	}
 
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的文章ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidArticleID)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
 		return
 	}
 
-	var article models.Article
-	if err := json.NewDecoder(r.Body).Decode(&article); err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的请求数据")
+	if errs := utils.Validate(req); errs != nil {
+		utils.SendValidationError(w, r, errs)
 		return
 	}
 
-	if err := c.articleService.UpdateArticle(id, &article); err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "更新文章失败: "+err.Error())
+	userID, _ := middleware.UserIDFromContext(r.Context())
+	patch := req.toPatch()
+	if err := c.articleService.UpdateArticle(id, userID, serializers.ViewerFrom(r).IsAdmin(), &patch); err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdateArticleFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "文章更新成功", nil)
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgArticleUpdated, nil)
 }
 
 // DeleteArticle 删除文章
 func (c *ArticleController) DeleteArticle(w http.ResponseWriter, r *http.Request) {
 	if c.articleService == nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "服务未初始化")
+		utils.SendErrorResponse(w, r, http.StatusInternalServerError, i18n.MsgServiceUnavailable)
 		return
 	}
 
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的文章ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidArticleID)
 		return
 	}
 
-	if err := c.articleService.DeleteArticle(id); err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "删除文章失败: "+err.Error())
+	userID, _ := middleware.UserIDFromContext(r.Context())
+	if err := c.articleService.DeleteArticle(id, userID, serializers.ViewerFrom(r).IsAdmin()); err != nil {
+		utils.SendError(w, r, err, i18n.MsgDeleteArticleFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "文章删除成功", nil)
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgArticleDeleted, nil)
 }
 
 // GetArticlesByCategory 获取分类下的所有文章
 func (c *ArticleController) GetArticlesByCategory(w http.ResponseWriter, r *http.Request) {
 	if c.articleService == nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "服务未初始化")
+		utils.SendErrorResponse(w, r, http.StatusInternalServerError, i18n.MsgServiceUnavailable)
 		return
 	}
 
 	vars := mux.Vars(r)
 	categoryID, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的分类ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidCategoryID)
 		return
 	}
 
-	articles, err := c.articleService.GetArticlesByCategory(categoryID)
+	userID, _ := middleware.UserIDFromContext(r.Context())
+	articles, err := c.articleService.GetArticlesByCategory(categoryID, userID)
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "获取文章列表失败: "+err.Error())
+		utils.SendError(w, r, err, i18n.MsgGetArticlesFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "成功", articles)
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.Articles(articles))
 }
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"my_blog/i18n"
+	"my_blog/middleware"
+	"my_blog/serializers"
+	"my_blog/services"
+	"my_blog/utils"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type AuthorController struct {
+	authorService *services.AuthorService
+}
+
+func NewAuthorController() *AuthorController {
+	return &AuthorController{
+		authorService: services.NewAuthorService(),
+	}
+}
+
+// GetAuthor 获取作者公开主页
+func (c *AuthorController) GetAuthor(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	author, err := c.authorService.GetAuthorProfile(mux.Vars(r)["username"], userID)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetAuthorFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.Author(author))
+}
+
+// GetAuthorArticles 分页获取作者发布的文章，支持 limit、offset 参数
+func (c *AuthorController) GetAuthorArticles(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	limit, offset, ok := parsePagination(r)
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	articles, err := c.authorService.GetAuthorArticles(mux.Vars(r)["username"], userID, limit, offset)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetArticlesFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.ArticleList(articles))
+}
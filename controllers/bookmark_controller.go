@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"my_blog/i18n"
+	"my_blog/middleware"
+	"my_blog/serializers"
+	"my_blog/services"
+	"my_blog/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type BookmarkController struct {
+	bookmarkService *services.BookmarkService
+}
+
+func NewBookmarkController() *BookmarkController {
+	return &BookmarkController{
+		bookmarkService: services.NewBookmarkService(),
+	}
+}
+
+// GetBookmarks 分页获取当前用户收藏的文章，支持 limit、offset 参数
+func (c *BookmarkController) GetBookmarks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	limit, offset, ok := parsePagination(r)
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	bookmarks, err := c.bookmarkService.GetBookmarks(userID, limit, offset)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetBookmarksFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.ArticleList(bookmarks))
+}
+
+// AddBookmark 收藏文章
+func (c *BookmarkController) AddBookmark(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	articleID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidArticleID)
+		return
+	}
+
+	if err := c.bookmarkService.AddBookmark(userID, articleID); err != nil {
+		utils.SendError(w, r, err, i18n.MsgBookmarkFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgBookmarked, nil)
+}
+
+// RemoveBookmark 取消收藏文章
+func (c *BookmarkController) RemoveBookmark(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	articleID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidArticleID)
+		return
+	}
+
+	if err := c.bookmarkService.RemoveBookmark(userID, articleID); err != nil {
+		utils.SendError(w, r, err, i18n.MsgBookmarkFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgBookmarkRemoved, nil)
+}
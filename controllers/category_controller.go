@@ -2,7 +2,9 @@ package controllers
 
 import (
 	"encoding/json"
+	"my_blog/i18n"
 	"my_blog/models"
+	"my_blog/serializers"
 	"my_blog/services"
 	"my_blog/utils"
 	"net/http"
@@ -11,6 +13,12 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// categoryRequest 创建/更新分类的请求数据
+type categoryRequest struct {
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
 type CategoryController struct {
 	categoryService *services.CategoryService
 }
@@ -25,11 +33,11 @@ func NewCategoryController() *CategoryController {
 func (c *CategoryController) GetCategories(w http.ResponseWriter, r *http.Request) {
 	categories, err := c.categoryService.GetAllCategories()
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "获取分类列表失败")
+		utils.SendError(w, r, err, i18n.MsgGetCategoriesFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "成功", categories)
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.Categories(categories))
 }
 
 // GetCategory 获取单个分类
@@ -37,44 +45,40 @@ func (c *CategoryController) GetCategory(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的分类ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidCategoryID)
 		return
 	}
 
 	category, err := c.categoryService.GetCategoryByID(id)
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "获取分类失败")
-		return
-	}
-
-	if category == nil {
-		utils.SendErrorResponse(w, http.StatusNotFound, "分类不存在")
+		utils.SendError(w, r, err, i18n.MsgGetCategoryFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "成功", category)
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.Category(category))
 }
 
 // CreateCategory 创建分类
 func (c *CategoryController) CreateCategory(w http.ResponseWriter, r *http.Request) {
-	var category models.Category
-	if err := json.NewDecoder(r.Body).Decode(&category); err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的请求数据")
+	var req categoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
 		return
 	}
 
-	if category.Name == "" {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "分类名称不能为空")
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
 		return
 	}
 
+	category := models.Category{Name: req.Name, Description: req.Description}
 	id, err := c.categoryService.CreateCategory(&category)
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "创建分类失败")
+		utils.SendError(w, r, err, i18n.MsgCreateCategoryFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusCreated, "分类创建成功", map[string]interface{}{"id": id})
+	utils.SendResponse(w, r, http.StatusCreated, i18n.MsgCategoryCreated, map[string]interface{}{"id": id})
 }
 
 // UpdateCategory 更新分类
@@ -82,22 +86,28 @@ func (c *CategoryController) UpdateCategory(w http.ResponseWriter, r *http.Reque
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的分类ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidCategoryID)
+		return
+	}
+
+	var req categoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
 		return
 	}
 
-	var category models.Category
-	if err := json.NewDecoder(r.Body).Decode(&category); err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的请求数据")
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
 		return
 	}
 
+	category := models.Category{Name: req.Name, Description: req.Description}
 	if err := c.categoryService.UpdateCategory(id, &category); err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "更新分类失败")
+		utils.SendError(w, r, err, i18n.MsgUpdateCategoryFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "分类更新成功", nil)
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgCategoryUpdated, nil)
 }
 
 // DeleteCategory 删除分类
@@ -105,14 +115,14 @@ func (c *CategoryController) DeleteCategory(w http.ResponseWriter, r *http.Reque
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的分类ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidCategoryID)
 		return
 	}
 
 	if err := c.categoryService.DeleteCategory(id); err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "删除分类失败")
+		utils.SendError(w, r, err, i18n.MsgDeleteCategoryFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "分类删除成功", nil)
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgCategoryDeleted, nil)
 }
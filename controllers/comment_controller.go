@@ -2,7 +2,11 @@ package controllers
 
 import (
 	"encoding/json"
+	"log/slog"
+	"my_blog/i18n"
+	"my_blog/middleware"
 	"my_blog/models"
+	"my_blog/serializers"
 	"my_blog/services"
 	"my_blog/utils"
 	"net/http"
@@ -11,13 +15,24 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// createCommentRequest 发表评论请求数据，parent_id 为回复的评论，作者为当前登录用户
+type createCommentRequest struct {
+	Content  string `json:"content" validate:"required,max=2000"`
+	ParentID *int   `json:"parent_id" validate:"omitempty,min=1"`
+}
+
+// updateCommentRequest 更新评论请求数据
+type updateCommentRequest struct {
+	Content string `json:"content" validate:"required,max=2000"`
+}
+
 type CommentController struct {
 	commentService *services.CommentService
 }
 
-func NewCommentController() *CommentController {
+func NewCommentController(logger *slog.Logger) *CommentController {
 	return &CommentController{
-		commentService: &services.CommentService{},
+		commentService: services.NewCommentService(logger),
 	}
 }
 
@@ -26,17 +41,18 @@ func (c *CommentController) GetCommentsByArticle(w http.ResponseWriter, r *http.
 	vars := mux.Vars(r)
 	articleID, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的文章ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidArticleID)
 		return
 	}
 
-	comments, err := c.commentService.GetCommentsByArticle(articleID)
+	userID, _ := middleware.UserIDFromContext(r.Context())
+	comments, err := c.commentService.GetCommentsByArticle(articleID, userID)
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "获取评论列表失败")
+		utils.SendError(w, r, err, i18n.MsgGetCommentsFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "成功", comments)
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.Comments(comments))
 }
 
 // CreateComment 创建评论
@@ -44,30 +60,36 @@ func (c *CommentController) CreateComment(w http.ResponseWriter, r *http.Request
 	vars := mux.Vars(r)
 	articleID, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的文章ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidArticleID)
 		return
 	}
 
-	var comment models.Comment
-	if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的请求数据")
+	var req createCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
 		return
 	}
 
-	comment.ArticleID = articleID
-
-	if comment.Content == "" || comment.Author == "" {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "评论内容和作者不能为空")
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
 		return
 	}
 
+	userID, _ := middleware.UserIDFromContext(r.Context())
+	comment := models.Comment{
+		ArticleID: articleID,
+		Content:   req.Content,
+		UserID:    userID,
+		ParentID:  req.ParentID,
+	}
+
 	id, err := c.commentService.CreateComment(&comment)
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "创建评论失败")
+		utils.SendError(w, r, err, i18n.MsgCreateCommentFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusCreated, "评论创建成功", map[string]interface{}{"id": id})
+	utils.SendResponse(w, r, http.StatusCreated, i18n.MsgCommentCreated, map[string]interface{}{"id": id})
 }
 
 // UpdateComment 更新评论
@@ -75,30 +97,28 @@ func (c *CommentController) UpdateComment(w http.ResponseWriter, r *http.Request
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的评论ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidCommentID)
 		return
 	}
 
-	var req struct {
-		Content string `json:"content"`
-	}
-
+	var req updateCommentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的请求数据")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
 		return
 	}
 
-	if req.Content == "" {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "评论内容不能为空")
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
 		return
 	}
 
-	if err := c.commentService.UpdateComment(id, req.Content); err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "更新评论失败")
+	userID, _ := middleware.UserIDFromContext(r.Context())
+	if err := c.commentService.UpdateComment(id, userID, serializers.ViewerFrom(r).IsAdmin(), req.Content); err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdateCommentFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "评论更新成功", nil)
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgCommentUpdated, nil)
 }
 
 // DeleteComment 删除评论
@@ -106,14 +126,15 @@ func (c *CommentController) DeleteComment(w http.ResponseWriter, r *http.Request
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的评论ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidCommentID)
 		return
 	}
 
-	if err := c.commentService.DeleteComment(id); err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "删除评论失败")
+	userID, _ := middleware.UserIDFromContext(r.Context())
+	if err := c.commentService.DeleteComment(id, userID, serializers.ViewerFrom(r).IsAdmin()); err != nil {
+		utils.SendError(w, r, err, i18n.MsgDeleteCommentFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "评论删除成功", nil)
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgCommentDeleted, nil)
 }
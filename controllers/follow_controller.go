@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"my_blog/i18n"
+	"my_blog/middleware"
+	"my_blog/serializers"
+	"my_blog/services"
+	"my_blog/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type FollowController struct {
+	followService *services.FollowService
+}
+
+func NewFollowController() *FollowController {
+	return &FollowController{
+		followService: services.NewFollowService(),
+	}
+}
+
+// FollowUser 关注用户
+func (c *FollowController) FollowUser(w http.ResponseWriter, r *http.Request) {
+	c.updateFollow(w, r, i18n.MsgInvalidUserID, i18n.MsgFollowed, c.followService.FollowUser)
+}
+
+// UnfollowUser 取消关注用户
+func (c *FollowController) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	c.updateFollow(w, r, i18n.MsgInvalidUserID, i18n.MsgUnfollowed, c.followService.UnfollowUser)
+}
+
+// FollowCategory 关注分类
+func (c *FollowController) FollowCategory(w http.ResponseWriter, r *http.Request) {
+	c.updateFollow(w, r, i18n.MsgInvalidCategoryID, i18n.MsgFollowed, c.followService.FollowCategory)
+}
+
+// UnfollowCategory 取消关注分类
+func (c *FollowController) UnfollowCategory(w http.ResponseWriter, r *http.Request) {
+	c.updateFollow(w, r, i18n.MsgInvalidCategoryID, i18n.MsgUnfollowed, c.followService.UnfollowCategory)
+}
+
+// updateFollow 解析路径中的目标ID并执行关注或取消关注
+func (c *FollowController) updateFollow(w http.ResponseWriter, r *http.Request, invalidIDMsg, successMsg string, update func(userID, targetID int) error) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	targetID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, invalidIDMsg)
+		return
+	}
+
+	if err := update(userID, targetID); err != nil {
+		utils.SendError(w, r, err, i18n.MsgFollowFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, successMsg, nil)
+}
+
+// GetFeed 获取当前用户关注的作者和分类的文章，支持 cursor、limit 参数
+func (c *FollowController) GetFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	cursor, limit, ok := parseCursorPagination(r)
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	feed, err := c.followService.Feed(userID, cursor, limit)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetFeedFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.ArticleFeed(feed))
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"context"
+	"my_blog/i18n"
+	"my_blog/logging"
+	"my_blog/services"
+	"my_blog/utils"
+	"net/http"
+	"time"
+)
+
+// readinessTimeout 就绪检查中数据库探测的超时时间
+const readinessTimeout = 2 * time.Second
+
+type HealthController struct {
+	healthService *services.HealthService
+}
+
+func NewHealthController() *HealthController {
+	return &HealthController{
+		healthService: &services.HealthService{},
+	}
+}
+
+// Healthz 存活检查，进程能处理请求即返回200
+func (c *HealthController) Healthz(w http.ResponseWriter, r *http.Request) {
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgSuccess, map[string]string{"status": "ok"})
+}
+
+// Readyz 就绪检查，数据库不可用或服务正在关闭时返回503
+func (c *HealthController) Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	if err := c.healthService.Ready(ctx); err != nil {
+		logging.FromContext(r.Context()).Warn("readiness check failed", "error", err)
+		utils.SendErrorResponse(w, r, http.StatusServiceUnavailable, i18n.MsgServiceUnavailable)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgSuccess, map[string]string{"status": "ready"})
+}
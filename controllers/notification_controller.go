@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"my_blog/i18n"
+	"my_blog/middleware"
+	"my_blog/serializers"
+	"my_blog/services"
+	"my_blog/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type NotificationController struct {
+	notificationService *services.NotificationService
+}
+
+func NewNotificationController(logger *slog.Logger) *NotificationController {
+	return &NotificationController{
+		notificationService: services.NewNotificationService(logger),
+	}
+}
+
+// GetPreferences 获取当前用户的邮件通知设置
+func (c *NotificationController) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	prefs, err := c.notificationService.GetPreferences(userID)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetPreferencesFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.NotificationPreferences(prefs))
+}
+
+// UpdatePreferences 更新当前用户的邮件通知设置，请求中未出现的项保持不变
+func (c *NotificationController) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	prefs, err := c.notificationService.GetPreferences(userID)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdatePreferencesFailed)
+		return
+	}
+
+	// 在当前设置上解码，只覆盖请求中给出的字段
+	update := *prefs
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if err := c.notificationService.UpdatePreferences(userID, &update); err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdatePreferencesFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgPreferencesUpdated, serializers.NotificationPreferences(&update))
+}
+
+// ListNotifications 获取当前用户的站内通知，支持 unread=true、limit、offset 参数
+func (c *NotificationController) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	limit, offset, ok := parsePagination(r)
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+	unreadOnly, _ := strconv.ParseBool(r.URL.Query().Get("unread"))
+
+	list, err := c.notificationService.List(userID, unreadOnly, limit, offset)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetNotificationsFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.NotificationList(list))
+}
+
+// MarkRead 将一条站内通知标记为已读
+func (c *NotificationController) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidNotificationID)
+		return
+	}
+
+	if err := c.notificationService.MarkRead(userID, id); err != nil {
+		utils.SendError(w, r, err, i18n.MsgMarkReadFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgNotificationRead, nil)
+}
+
+// MarkAllRead 将当前用户的所有站内通知标记为已读
+func (c *NotificationController) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	count, err := c.notificationService.MarkAllRead(userID)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgMarkReadFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgNotificationRead, map[string]interface{}{"count": count})
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// 分页参数：limit 默认 defaultPageLimit 条，最多 maxPageLimit 条
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// parsePagination 解析 limit、offset 查询参数，参数不合法时返回false
+func parsePagination(r *http.Request) (limit, offset int, ok bool) {
+	query := r.URL.Query()
+	limit, err := queryInt(query.Get("limit"), defaultPageLimit)
+	if err != nil || limit < 1 || limit > maxPageLimit {
+		return 0, 0, false
+	}
+	offset, err = queryInt(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		return 0, 0, false
+	}
+	return limit, offset, true
+}
+
+// parseCursorPagination 解析 cursor、limit 查询参数，limit 不合法时返回false
+func parseCursorPagination(r *http.Request) (cursor string, limit int, ok bool) {
+	query := r.URL.Query()
+	limit, err := queryInt(query.Get("limit"), defaultPageLimit)
+	if err != nil || limit < 1 || limit > maxPageLimit {
+		return "", 0, false
+	}
+	return query.Get("cursor"), limit, true
+}
+
+// queryInt 解析整数查询参数，参数为空时返回默认值
+func queryInt(value string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"encoding/json"
+	"my_blog/i18n"
+	"my_blog/middleware"
+	"my_blog/models"
+	"my_blog/services"
+	"my_blog/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// reactionRequest 切换反应的请求数据
+type reactionRequest struct {
+	Reaction string `json:"reaction" validate:"required"`
+}
+
+// reactionResponse 切换后的结果：Reacted 表示当前用户现在是否有该反应
+type reactionResponse struct {
+	Reacted bool `json:"reacted"`
+	*models.ReactionSummary
+}
+
+type ReactionController struct {
+	reactionService *services.ReactionService
+}
+
+func NewReactionController() *ReactionController {
+	return &ReactionController{
+		reactionService: &services.ReactionService{},
+	}
+}
+
+// ToggleArticleReaction 切换当前用户对文章的反应
+func (c *ReactionController) ToggleArticleReaction(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidArticleID)
+		return
+	}
+	c.toggle(w, r, id, c.reactionService.ToggleArticleReaction)
+}
+
+// ToggleCommentReaction 切换当前用户对评论的反应
+func (c *ReactionController) ToggleCommentReaction(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidCommentID)
+		return
+	}
+	c.toggle(w, r, id, c.reactionService.ToggleCommentReaction)
+}
+
+// toggle 解析请求并调用对应内容的切换方法
+func (c *ReactionController) toggle(w http.ResponseWriter, r *http.Request, id int,
+	toggleFn func(id, userID int, reaction string) (bool, *models.ReactionSummary, error)) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	var req reactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
+		return
+	}
+
+	reacted, summary, err := toggleFn(id, userID, req.Reaction)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgReactFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgReactionToggled, reactionResponse{Reacted: reacted, ReactionSummary: summary})
+}
@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"encoding/json"
+	"my_blog/i18n"
+	"my_blog/middleware"
+	"my_blog/models"
+	"my_blog/serializers"
+	"my_blog/services"
+	"my_blog/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// readingListRequest 创建/更新阅读列表的请求数据
+type readingListRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
+type ReadingListController struct {
+	readingListService *services.ReadingListService
+}
+
+func NewReadingListController() *ReadingListController {
+	return &ReadingListController{
+		readingListService: services.NewReadingListService(),
+	}
+}
+
+// GetReadingLists 获取当前用户的所有阅读列表
+func (c *ReadingListController) GetReadingLists(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	lists, err := c.readingListService.GetReadingLists(userID)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetReadingListsFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.ReadingLists(lists))
+}
+
+// GetReadingList 获取阅读列表及其中的文章
+func (c *ReadingListController) GetReadingList(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := c.listParams(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := c.readingListService.GetReadingList(userID, id)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetReadingListsFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.ReadingList(list))
+}
+
+// CreateReadingList 创建阅读列表
+func (c *ReadingListController) CreateReadingList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	var req readingListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
+		return
+	}
+
+	list := models.ReadingList{Name: req.Name, Description: req.Description}
+	if err := c.readingListService.CreateReadingList(userID, &list); err != nil {
+		utils.SendError(w, r, err, i18n.MsgCreateReadingListFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusCreated, i18n.MsgReadingListCreated, serializers.ReadingList(&list))
+}
+
+// UpdateReadingList 更新阅读列表的名称和描述
+func (c *ReadingListController) UpdateReadingList(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := c.listParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req readingListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
+		return
+	}
+
+	list := models.ReadingList{Name: req.Name, Description: req.Description}
+	if err := c.readingListService.UpdateReadingList(userID, id, &list); err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdateReadingListFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgReadingListUpdated, nil)
+}
+
+// DeleteReadingList 删除阅读列表
+func (c *ReadingListController) DeleteReadingList(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := c.listParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.readingListService.DeleteReadingList(userID, id); err != nil {
+		utils.SendError(w, r, err, i18n.MsgDeleteReadingListFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgReadingListDeleted, nil)
+}
+
+// AddArticle 将文章添加到阅读列表末尾
+func (c *ReadingListController) AddArticle(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := c.listParams(w, r)
+	if !ok {
+		return
+	}
+
+	articleID, err := strconv.Atoi(mux.Vars(r)["articleId"])
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidArticleID)
+		return
+	}
+
+	if err := c.readingListService.AddArticle(userID, id, articleID); err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdateReadingListFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgReadingListUpdated, nil)
+}
+
+// RemoveArticle 从阅读列表中移除文章
+func (c *ReadingListController) RemoveArticle(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := c.listParams(w, r)
+	if !ok {
+		return
+	}
+
+	articleID, err := strconv.Atoi(mux.Vars(r)["articleId"])
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidArticleID)
+		return
+	}
+
+	if err := c.readingListService.RemoveArticle(userID, id, articleID); err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdateReadingListFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgReadingListUpdated, nil)
+}
+
+// ReorderArticles 调整阅读列表中文章的顺序，article_ids 须包含列表中的全部文章
+func (c *ReadingListController) ReorderArticles(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := c.listParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ArticleIDs []int `json:"article_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if err := c.readingListService.ReorderArticles(userID, id, req.ArticleIDs); err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdateReadingListFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgReadingListUpdated, nil)
+}
+
+// listParams 获取当前用户和路径中的阅读列表ID，失败时已写入错误响应
+func (c *ReadingListController) listParams(w http.ResponseWriter, r *http.Request) (userID, id int, ok bool) {
+	userID, ok = middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return 0, 0, false
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidReadingListID)
+		return 0, 0, false
+	}
+	return userID, id, true
+}
@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"my_blog/i18n"
+	"my_blog/serializers"
+	"my_blog/utils"
+	"net/http"
+)
+
+// sendResource 按 fields 查询参数筛选响应字段后发送，data 应为 serializers 生成的响应类型
+func sendResource(w http.ResponseWriter, r *http.Request, statusCode int, code string, data interface{}) {
+	shaped, err := serializers.Sparse(data, serializers.Fields(r))
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgInternalError)
+		return
+	}
+	utils.SendResponse(w, r, statusCode, code, shaped)
+}
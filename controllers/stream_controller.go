@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"my_blog/config"
+	"my_blog/i18n"
+	"my_blog/logging"
+	"my_blog/middleware"
+	"my_blog/realtime"
+	"my_blog/utils"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// streamRetry 客户端断线后重新连接的等待时间（毫秒）
+const streamRetry = 3000
+
+type StreamController struct {
+	hub *realtime.Hub
+}
+
+func NewStreamController() *StreamController {
+	return &StreamController{hub: config.Realtime}
+}
+
+// Events 通过 Server-Sent Events 推送实时事件：新文章公告；带 article 参数时推送该文章的评论；
+// 已登录时推送当前用户的站内通知。订阅因消费过慢被断开时客户端会自动重连
+func (c *StreamController) Events(w http.ResponseWriter, r *http.Request) {
+	topics := []string{realtime.ArticlesTopic}
+	if value := r.URL.Query().Get("article"); value != "" {
+		articleID, err := strconv.Atoi(value)
+		if err != nil {
+			utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidArticleID)
+			return
+		}
+		topics = append(topics, realtime.CommentsTopic(articleID))
+	}
+	if userID, ok := middleware.UserIDFromContext(r.Context()); ok {
+		topics = append(topics, realtime.UserTopic(userID))
+	}
+
+	rc := http.NewResponseController(w)
+	// 长连接不受服务器写超时限制
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		utils.SendError(w, r, err, i18n.MsgServiceUnavailable)
+		return
+	}
+
+	sub := c.hub.Subscribe(topics...)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// 禁用 nginx 的响应缓冲
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	logger := logging.FromContext(r.Context())
+	heartbeat := time.NewTicker(config.StreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sub.Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case event := <-sub.Events():
+			data, err := json.Marshal(event.Data)
+			if err != nil {
+				logger.Error("encode stream event failed", "type", event.Type, "error", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"log/slog"
+	"my_blog/i18n"
+	"my_blog/middleware"
+	"my_blog/serializers"
+	"my_blog/services"
+	"my_blog/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// TrashController 回收站：作者查看和恢复自己的内容，管理员查看和恢复所有内容
+type TrashController struct {
+	trashService *services.TrashService
+}
+
+// NewTrashController 创建回收站控制器
+func NewTrashController(logger *slog.Logger) *TrashController {
+	return &TrashController{
+		trashService: services.NewTrashService(logger),
+	}
+}
+
+// GetTrashedArticles 分页获取回收站中的文章，支持 limit、offset 参数
+func (c *TrashController) GetTrashedArticles(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	limit, offset, ok := parsePagination(r)
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	list, err := c.trashService.ListArticles(userID, serializers.ViewerFrom(r).IsAdmin(), limit, offset)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetTrashFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.TrashedArticleList(list))
+}
+
+// GetTrashedComments 分页获取回收站中的评论，支持 limit、offset 参数
+func (c *TrashController) GetTrashedComments(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	limit, offset, ok := parsePagination(r)
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	list, err := c.trashService.ListComments(userID, serializers.ViewerFrom(r).IsAdmin(), limit, offset)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetTrashFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.TrashedCommentList(list))
+}
+
+// RestoreArticle 从回收站恢复文章
+func (c *TrashController) RestoreArticle(w http.ResponseWriter, r *http.Request) {
+	c.restore(w, r, i18n.MsgInvalidArticleID, i18n.MsgArticleRestored, i18n.MsgRestoreArticleFailed, c.trashService.RestoreArticle)
+}
+
+// RestoreComment 从回收站恢复评论
+func (c *TrashController) RestoreComment(w http.ResponseWriter, r *http.Request) {
+	c.restore(w, r, i18n.MsgInvalidCommentID, i18n.MsgCommentRestored, i18n.MsgRestoreCommentFailed, c.trashService.RestoreComment)
+}
+
+// restore 解析路径中的ID并以当前用户的身份执行恢复
+func (c *TrashController) restore(w http.ResponseWriter, r *http.Request, invalidIDMsg, successMsg, failedMsg string,
+	restore func(id, userID int, admin bool) error) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, invalidIDMsg)
+		return
+	}
+
+	if err := restore(id, userID, serializers.ViewerFrom(r).IsAdmin()); err != nil {
+		utils.SendError(w, r, err, failedMsg)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, successMsg, nil)
+}
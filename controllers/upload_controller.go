@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"my_blog/config"
+	"my_blog/i18n"
+	"my_blog/services"
+	"my_blog/storage"
+	"my_blog/utils"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+type UploadController struct {
+	imageService *services.ImageService
+}
+
+func NewUploadController() *UploadController {
+	return &UploadController{
+		imageService: &services.ImageService{},
+	}
+}
+
+// UploadImage 上传文章配图，返回 large 变体路径及各尺寸变体
+func (c *UploadController) UploadImage(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(2 << 20); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidForm)
+		return
+	}
+
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgImageRequired)
+		return
+	}
+	defer file.Close()
+
+	if err := services.ValidateImageUpload(fileHeader); err != nil {
+		utils.SendError(w, r, err, i18n.MsgInvalidImage)
+		return
+	}
+
+	imagePath, err := c.imageService.ProcessAndSave(file)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgSaveImageFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusCreated, i18n.MsgUploaded, map[string]interface{}{
+		"url":      imagePath,
+		"variants": services.ImageVariantsFor(imagePath),
+	})
+}
+
+// uploadContentTypes 允许对外提供的上传文件类型，其余扩展名一律返回404
+var uploadContentTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+}
+
+const (
+	immutableCacheControl = "public, max-age=31536000, immutable"
+	defaultCacheControl   = "public, max-age=3600"
+	signedURLExpiry       = 15 * time.Minute
+)
+
+// ServeUpload 提供 /uploads/ 下的上传文件，支持条件请求和范围请求，禁止目录浏览和路径穿越。
+// 本地存储的签名地址在此校验签名和过期时间
+func (c *UploadController) ServeUpload(w http.ResponseWriter, r *http.Request) {
+	key, err := storage.CleanKey(strings.TrimPrefix(r.URL.Path, config.UploadURLPrefix))
+	if err != nil || strings.HasSuffix(r.URL.Path, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	contentType, ok := uploadContentTypes[strings.ToLower(path.Ext(key))]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	// 远程存储直接重定向到签名地址，由存储后端处理范围请求和缓存
+	local, ok := config.Storage.(*storage.LocalStorage)
+	if !ok {
+		signedURL, err := config.Storage.SignedURL(key, signedURLExpiry)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, signedURL, http.StatusFound)
+		return
+	}
+
+	// 带签名参数的地址（由 SignedURL 生成）必须签名有效且未过期
+	query := r.URL.Query()
+	if query.Has("signature") || query.Has("expires") {
+		if !local.VerifySignedURL(key, query) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+	}
+
+	obj, err := config.Storage.Stat(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	file, err := config.Storage.Get(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", contentType)
+	header.Set("X-Content-Type-Options", "nosniff")
+	if hash := services.UploadContentHash(key); hash != "" {
+		header.Set("Cache-Control", immutableCacheControl)
+		header.Set("ETag", `"`+hash+path.Ext(key)+`"`)
+	} else {
+		header.Set("Cache-Control", defaultCacheControl)
+		header.Set("ETag", fmt.Sprintf(`"%x-%x"`, obj.ModTime.Unix(), obj.Size))
+	}
+
+	http.ServeContent(w, r, key, obj.ModTime, content)
+}
@@ -3,159 +3,377 @@ package controllers
 import (
 	"encoding/json"
 	"github.com/gorilla/mux"
+	"log/slog"
+	"my_blog/i18n"
+	"my_blog/metrics"
 	"my_blog/middleware"
 	"my_blog/models"
+	"my_blog/serializers"
 	"my_blog/services"
 	"my_blog/utils"
 	"net/http"
 	"strconv"
+	"time"
 )
 
+// tokenExpiration 登录token有效期
+const tokenExpiration = 24 * time.Hour
+
+// registerRequest 注册请求数据
+type registerRequest struct {
+	Username string `json:"username" validate:"required,username"`
+	Password string `json:"password" validate:"required,password"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+}
+
+// updateUserRequest 部分更新用户信息请求数据（JSON Merge Patch），只修改出现的字段；
+// locale、bio、website、location 为 null 时清空，social_links 按链接合并
+type updateUserRequest struct {
+	Username    models.Optional[string]                  `json:"username" validate:"required,username"`
+	Email       models.Optional[string]                  `json:"email" validate:"required,email,max=255"`
+	Password    models.Optional[string]                  `json:"password" validate:"required,password"`
+	ImageData   models.Optional[string]                  `json:"image_data" validate:"required,max=255"`
+	Locale      models.Optional[string]                  `json:"locale" validate:"omitempty,oneof=zh-CN en"`
+	Bio         models.Optional[string]                  `json:"bio" validate:"max=500"`
+	Website     models.Optional[string]                  `json:"website" validate:"omitempty,url,max=255"`
+	Location    models.Optional[string]                  `json:"location" validate:"max=100"`
+	SocialLinks models.Optional[patchSocialLinksRequest] `json:"social_links"`
+}
+
+// patchSocialLinksRequest 社交链接的部分更新，为 null 的链接被移除
+type patchSocialLinksRequest struct {
+	GitHub   models.Optional[string] `json:"github" validate:"omitempty,url,max=255"`
+	Twitter  models.Optional[string] `json:"twitter" validate:"omitempty,url,max=255"`
+	Weibo    models.Optional[string] `json:"weibo" validate:"omitempty,url,max=255"`
+	Zhihu    models.Optional[string] `json:"zhihu" validate:"omitempty,url,max=255"`
+	LinkedIn models.Optional[string] `json:"linkedin" validate:"omitempty,url,max=255"`
+}
+
+// validate 校验出现的字段，社交链接的字段名带 social_links. 前缀
+func (req *updateUserRequest) validate() []models.FieldError {
+	errs := utils.Validate(req)
+	if req.SocialLinks.Set && !req.SocialLinks.Null {
+		for _, e := range utils.Validate(&req.SocialLinks.Value) {
+			e.Field = "social_links." + e.Field
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+func (req *updateUserRequest) toPatch() models.UserPatch {
+	links := req.SocialLinks.Value
+	return models.UserPatch{
+		Username:  req.Username,
+		Email:     req.Email,
+		Password:  req.Password,
+		ImageData: req.ImageData,
+		Locale:    req.Locale,
+		Bio:       req.Bio,
+		Website:   req.Website,
+		Location:  req.Location,
+		SocialLinks: models.Optional[models.SocialLinksPatch]{
+			Set:  req.SocialLinks.Set,
+			Null: req.SocialLinks.Null,
+			Value: models.SocialLinksPatch{
+				GitHub:   links.GitHub,
+				Twitter:  links.Twitter,
+				Weibo:    links.Weibo,
+				Zhihu:    links.Zhihu,
+				LinkedIn: links.LinkedIn,
+			},
+		},
+	}
+}
+
+// formValue 读取 multipart 表单中出现的字段，未出现时为未设置
+func formValue(r *http.Request, key string) models.Optional[string] {
+	values, ok := r.MultipartForm.Value[key]
+	if !ok || len(values) == 0 {
+		return models.Optional[string]{}
+	}
+	return models.Some(values[0])
+}
+
+// updateProfileRequest 更新个人资料请求数据，整体替换原有资料
+type updateProfileRequest struct {
+	Bio         string             `json:"bio" validate:"max=500"`
+	Website     string             `json:"website" validate:"omitempty,url,max=255"`
+	Location    string             `json:"location" validate:"max=100"`
+	SocialLinks socialLinksRequest `json:"social_links"`
+}
+
+// socialLinksRequest 社交账号链接，每项须为 http 或 https 地址
+type socialLinksRequest struct {
+	GitHub   string `json:"github" validate:"omitempty,url,max=255"`
+	Twitter  string `json:"twitter" validate:"omitempty,url,max=255"`
+	Weibo    string `json:"weibo" validate:"omitempty,url,max=255"`
+	Zhihu    string `json:"zhihu" validate:"omitempty,url,max=255"`
+	LinkedIn string `json:"linkedin" validate:"omitempty,url,max=255"`
+}
+
+// validate 校验资料及社交链接，社交链接的字段名带 social_links. 前缀
+func (req *updateProfileRequest) validate() []models.FieldError {
+	errs := utils.Validate(req)
+	for _, e := range utils.Validate(&req.SocialLinks) {
+		e.Field = "social_links." + e.Field
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+func (req updateProfileRequest) toProfile() models.Profile {
+	return models.Profile{
+		Bio:      req.Bio,
+		Website:  req.Website,
+		Location: req.Location,
+		SocialLinks: models.SocialLinks{
+			GitHub:   req.SocialLinks.GitHub,
+			Twitter:  req.SocialLinks.Twitter,
+			Weibo:    req.SocialLinks.Weibo,
+			Zhihu:    req.SocialLinks.Zhihu,
+			LinkedIn: req.SocialLinks.LinkedIn,
+		},
+	}
+}
+
 type UserController struct {
-	userService *services.UserService
+	userService    *services.UserService
+	accountService *services.AccountService
 }
 
-func NewUserController() *UserController {
+func NewUserController(logger *slog.Logger) *UserController {
 	return &UserController{
-		userService: &services.UserService{},
+		userService:    services.NewUserService(logger),
+		accountService: services.NewAccountService(logger),
 	}
 }
 
 // Register 用户注册（支持文件上传）
 func (c *UserController) Register(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseMultipartForm(2 << 20); err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的表单数据")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidForm)
 		return
 	}
 
-	var user models.User
-	user.Username = r.FormValue("username")
-	user.Password = r.FormValue("password")
-	user.Email = r.FormValue("email")
-
-	if user.Username == "" || user.Password == "" || user.Email == "" {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "用户名、密码和邮箱不能为空")
-		return
+	req := registerRequest{
+		Username: r.FormValue("username"),
+		Password: r.FormValue("password"),
+		Email:    r.FormValue("email"),
 	}
+	errs := utils.Validate(&req)
 
 	file, fileHeader, err := r.FormFile("avatar")
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "头像为必填项")
+		errs = append(errs, models.FieldError{Field: "avatar", Code: i18n.MsgFieldRequired})
+	} else {
+		defer file.Close()
+	}
+
+	if errs != nil {
+		utils.SendValidationError(w, r, errs)
 		return
 	}
-	defer file.Close()
+
+	user := models.User{
+		Username: req.Username,
+		Password: req.Password,
+		Email:    req.Email,
+	}
 
 	if err := c.userService.Register(&user, fileHeader); err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "注册失败")
+		utils.SendError(w, r, err, i18n.MsgRegisterFailed)
 		return
 	}
+	c.accountService.QueueVerificationEmail(user.ID)
 
-	utils.SendResponse(w, http.StatusCreated, "注册成功", nil)
+	utils.SendResponse(w, r, http.StatusCreated, i18n.MsgRegistered, nil)
 }
 
 // Login 用户登录
 func (c *UserController) Login(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
+		Username string `json:"username" validate:"required"`
+		Password string `json:"password" validate:"required"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的请求数据")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
 		return
 	}
 
 	user, err := c.userService.Login(req.Username, req.Password)
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusUnauthorized, "用户名或密码错误")
+		metrics.LoginAttempts.WithLabelValues("failure").Inc()
+		utils.SendError(w, r, err, i18n.MsgLoginFailed)
 		return
 	}
+	metrics.LoginAttempts.WithLabelValues("success").Inc()
 
-	token, err := middleware.GenerateToken(user.ID)
+	token, err := middleware.GenerateToken(user.ID, user.Username, tokenExpiration)
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "生成token失败")
+		utils.SendError(w, r, err, i18n.MsgTokenFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "登录成功", map[string]string{"token": token})
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgLoggedIn, map[string]string{"token": token})
 }
 
 // GetCurrentUser 获取当前用户信息
 func (c *UserController) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value("userID").(int)
+	userID, ok := middleware.UserIDFromContext(r.Context())
 	if !ok {
-		utils.SendErrorResponse(w, http.StatusUnauthorized, "未登录")
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	user, err := c.userService.GetUserByID(userID)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetUserFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.User(user, serializers.ViewerFrom(r)))
+}
+
+// UpdateProfile 更新当前用户的个人资料（简介、网站、所在地、社交链接），返回更新后的用户
+func (c *UserController) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
+	}
+
+	var req updateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if errs := req.validate(); errs != nil {
+		utils.SendValidationError(w, r, errs)
+		return
+	}
+
+	profile := req.toProfile()
+	if err := c.userService.UpdateProfile(userID, &profile); err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdateProfileFailed)
 		return
 	}
 
 	user, err := c.userService.GetUserByID(userID)
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "获取用户信息失败")
+		utils.SendError(w, r, err, i18n.MsgGetUserFailed)
 		return
 	}
 
-	if user == nil {
-		utils.SendErrorResponse(w, http.StatusNotFound, "用户不存在")
+	sendResource(w, r, http.StatusOK, i18n.MsgProfileUpdated, serializers.User(user, serializers.ViewerFrom(r)))
+}
+
+// UpdateCurrentUser 部分更新当前用户信息，PUT 和 PATCH 均只修改请求中出现的字段，返回更新后的用户
+func (c *UserController) UpdateCurrentUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "成功", user)
+	c.updateUser(w, r, userID)
 }
 
-// UpdateUser 更新用户信息
+// UpdateUser 部分更新指定用户的信息，只有本人和管理员可以修改
 func (c *UserController) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的用户ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidUserID)
 		return
 	}
 
-	// 解析表单数据（支持文件上传）
-	if err := r.ParseMultipartForm(2 << 20); err != nil {
-		// 如果解析失败，尝试作为JSON解析（不包含文件的情况）
-		var user models.User
-		if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-			utils.SendErrorResponse(w, http.StatusBadRequest, "无效的请求数据")
-			return
-		}
+	if !canManageUser(r, id) {
+		utils.SendErrorResponse(w, r, http.StatusForbidden, i18n.MsgPermissionDenied)
+		return
+	}
+
+	c.updateUser(w, r, id)
+}
 
-		if err := c.userService.UpdateUser(id, &user); err != nil {
-			utils.SendErrorResponse(w, http.StatusInternalServerError, "更新用户信息失败: "+err.Error())
-			return
+// canManageUser 只有本人和管理员可以修改用户信息
+func canManageUser(r *http.Request, id int) bool {
+	viewer := serializers.ViewerFrom(r)
+	return viewer.IsSelf(id) || viewer.IsAdmin()
+}
+
+// updateUser 解析 JSON 或 multipart 表单（可上传头像 avatar）并部分更新用户 id
+func (c *UserController) updateUser(w http.ResponseWriter, r *http.Request, id int) {
+	var req updateUserRequest
+	// 解析表单数据（支持文件上传）
+	multipart := r.ParseMultipartForm(2<<20) == nil
+	if multipart {
+		req = updateUserRequest{
+			Username: formValue(r, "username"),
+			Password: formValue(r, "password"),
+			Email:    formValue(r, "email"),
+			Locale:   formValue(r, "locale"),
+			Bio:      formValue(r, "bio"),
+			Website:  formValue(r, "website"),
+			Location: formValue(r, "location"),
 		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		// 不是表单时按JSON解析（不包含文件的情况）
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
 
-		utils.SendResponse(w, http.StatusOK, "用户信息更新成功", nil)
+	if errs := req.validate(); errs != nil {
+		utils.SendValidationError(w, r, errs)
 		return
 	}
 
-	// 处理包含文件上传的情况
-	var user models.User
-	user.Username = r.FormValue("username")
-	user.Password = r.FormValue("password")
-	user.Email = r.FormValue("email")
-	user.Status = parseStatus(r.FormValue("status"))
+	// 修改自己的密码需验证原密码，只能通过修改密码接口；管理员可直接重置其他用户的密码
+	viewer := serializers.ViewerFrom(r)
+	if req.Password.Set && viewer.IsSelf(id) {
+		utils.SendValidationError(w, r, []models.FieldError{{Field: "password", Code: i18n.MsgUsePasswordEndpoint}})
+		return
+	}
 
 	// 处理文件上传（如果有）
-	file, fileHeader, err := r.FormFile("avatar")
-	if err == nil {
-		defer file.Close()
-		// 生成唯一文件名（假设 services 包中有此函数，返回 string）
-		uniqueName := services.GenerateUniqueFileName(fileHeader.Filename)
-		user.ImageData = "/uploads/" + uniqueName
-		// 保存文件，SaveAvatar 仅返回 error
-		if err := c.userService.SaveAvatar(file, uniqueName); err != nil {
-			utils.SendErrorResponse(w, http.StatusInternalServerError, "保存头像失败")
-			return
+	if multipart {
+		file, fileHeader, err := r.FormFile("avatar")
+		if err == nil {
+			defer file.Close()
+			if err := services.ValidateImageUpload(fileHeader); err != nil {
+				utils.SendError(w, r, err, i18n.MsgInvalidImage)
+				return
+			}
+			// 生成多尺寸头像并保存，返回 large 变体路径
+			imagePath, err := c.userService.SaveAvatar(file)
+			if err != nil {
+				utils.SendError(w, r, err, i18n.MsgSaveAvatarFailed)
+				return
+			}
+			req.ImageData = models.Some(imagePath)
 		}
 	}
 
-	if err := c.userService.UpdateUser(id, &user); err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "更新用户信息失败: "+err.Error())
+	patch := req.toPatch()
+	if err := c.userService.UpdateUser(id, &patch); err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdateUserFailed)
+		return
+	}
+
+	user, err := c.userService.GetUserByID(id)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetUserFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "用户信息更新成功", nil)
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgUserUpdated, serializers.User(user, viewer))
 }
 
 // DeleteUser 删除用户
@@ -163,27 +381,35 @@ func (c *UserController) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的用户ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidUserID)
 		return
 	}
 
 	if err := c.userService.DeleteUser(id); err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "删除用户失败")
+		utils.SendError(w, r, err, i18n.MsgDeleteUserFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "用户删除成功", nil)
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgUserDeleted, nil)
 }
 
-// GetAllUsers 获取所有用户
+// GetAllUsers 获取所有用户，可通过 ?status= 按账号状态筛选
 func (c *UserController) GetAllUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := c.userService.GetAllUsers()
+	filter := struct {
+		Status string `json:"status" validate:"omitempty,oneof=active suspended banned deleted"`
+	}{Status: r.URL.Query().Get("status")}
+	if errs := utils.Validate(&filter); errs != nil {
+		utils.SendValidationError(w, r, errs)
+		return
+	}
+
+	users, err := c.userService.GetAllUsers(filter.Status)
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "获取用户列表失败")
+		utils.SendError(w, r, err, i18n.MsgGetUsersFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "成功", users)
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.Users(users, serializers.ViewerFrom(r)))
 }
 
 // UpdateUserRole 更新用户角色
@@ -191,37 +417,77 @@ func (c *UserController) UpdateUserRole(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的用户ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidUserID)
 		return
 	}
 
 	var req struct {
-		RoleID int `json:"role_id"`
+		RoleID int `json:"role_id" validate:"oneof=1 2 3"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的请求数据")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
 		return
 	}
 
 	if err := c.userService.UpdateUserRole(id, req.RoleID); err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "更新用户角色失败")
+		utils.SendError(w, r, err, i18n.MsgUpdateUserRoleFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "用户角色更新成功", nil)
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgUserRoleUpdated, nil)
+}
+
+// updateUserStatusRequest 修改账号状态请求，until 只能用于暂停，为空表示直到管理员解除
+type updateUserStatusRequest struct {
+	Status string     `json:"status" validate:"required,oneof=active suspended banned"`
+	Reason string     `json:"reason" validate:"max=500"`
+	Until  *time.Time `json:"until"`
 }
 
-// 辅助函数：解析状态值
-func parseStatus(statusStr string) int {
-	if statusStr == "" {
-		return 0 // 默认值
+// UpdateUserStatus 暂停、封禁或恢复用户账号
+func (c *UserController) UpdateUserStatus(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgNotLoggedIn)
+		return
 	}
-	status, err := strconv.Atoi(statusStr)
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		return 0 // 解析失败返回默认值
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidUserID)
+		return
 	}
-	return status
+
+	var req updateUserStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
+		return
+	}
+
+	change := &models.UserStatusChange{Status: req.Status, Reason: req.Reason, Until: req.Until}
+	if err := c.userService.UpdateUserStatus(id, actorID, change); err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdateUserStatusFailed)
+		return
+	}
+
+	user, err := c.userService.GetUserByID(id)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdateUserStatusFailed)
+		return
+	}
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgUserStatusUpdated, serializers.User(user, serializers.ViewerFrom(r)))
 }
 
 // UpdateUserBackgroundImage 更新用户背景图
@@ -229,38 +495,49 @@ func (c *UserController) UpdateUserBackgroundImage(w http.ResponseWriter, r *htt
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的用户ID")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidUserID)
+		return
+	}
+
+	if !canManageUser(r, id) {
+		utils.SendErrorResponse(w, r, http.StatusForbidden, i18n.MsgPermissionDenied)
 		return
 	}
 
 	// 解析表单数据
 	if err := r.ParseMultipartForm(2 << 20); err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "无效的表单数据")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidForm)
 		return
 	}
 
 	file, fileHeader, err := r.FormFile("background_image")
 	if err != nil {
-		utils.SendErrorResponse(w, http.StatusBadRequest, "背景图为必填项")
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgBackgroundRequired)
 		return
 	}
 	defer file.Close()
 
-	// 生成唯一文件名
-	uniqueName := services.GenerateUniqueFileName(fileHeader.Filename)
-	backgroundImagePath := "/uploads/backgrounds/" + uniqueName
+	// 校验文件类型和大小
+	if err := services.ValidateImageUpload(fileHeader); err != nil {
+		utils.SendError(w, r, err, i18n.MsgInvalidImage)
+		return
+	}
 
-	// 保存文件
-	if err := c.userService.SaveBackgroundImage(file, uniqueName); err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "保存背景图失败")
+	// 生成多尺寸背景图并保存
+	backgroundImagePath, err := c.userService.SaveBackgroundImage(file)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgSaveBackgroundFailed)
 		return
 	}
 
 	// 更新用户背景图
 	if err := c.userService.UpdateUserBackgroundImage(id, backgroundImagePath); err != nil {
-		utils.SendErrorResponse(w, http.StatusInternalServerError, "更新用户背景图失败")
+		utils.SendError(w, r, err, i18n.MsgUpdateBackgroundFailed)
 		return
 	}
 
-	utils.SendResponse(w, http.StatusOK, "用户背景图更新成功", nil)
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgBackgroundUpdated, map[string]interface{}{
+		"background_image":    backgroundImagePath,
+		"background_variants": services.ImageVariantsFor(backgroundImagePath),
+	})
 }
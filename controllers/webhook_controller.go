@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"my_blog/i18n"
+	"my_blog/models"
+	"my_blog/serializers"
+	"my_blog/services"
+	"my_blog/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// webhookRequest 创建/更新 Webhook 的请求数据，active 缺省时创建为启用、更新为保持不变
+type webhookRequest struct {
+	URL    string   `json:"url" validate:"required,url,max=512"`
+	Secret string   `json:"secret" validate:"omitempty,min=16,max=128"`
+	Events []string `json:"events" validate:"required"`
+	Active *bool    `json:"active"`
+}
+
+type WebhookController struct {
+	webhookService *services.WebhookService
+}
+
+func NewWebhookController(logger *slog.Logger) *WebhookController {
+	return &WebhookController{
+		webhookService: services.NewWebhookService(logger),
+	}
+}
+
+// GetWebhooks 获取所有 Webhook
+func (c *WebhookController) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := c.webhookService.GetWebhooks()
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetWebhooksFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.Webhooks(hooks))
+}
+
+// CreateWebhook 创建 Webhook，响应中包含签名密钥，之后不会再返回
+func (c *WebhookController) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
+		return
+	}
+
+	hook := models.Webhook{URL: req.URL, Secret: req.Secret, Events: req.Events, Active: true}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+	if err := c.webhookService.CreateWebhook(&hook); err != nil {
+		utils.SendError(w, r, err, i18n.MsgCreateWebhookFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusCreated, i18n.MsgWebhookCreated, serializers.CreatedWebhook(&hook))
+}
+
+// UpdateWebhook 更新 Webhook，secret 为空时保留原签名密钥
+func (c *WebhookController) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidWebhookID)
+		return
+	}
+
+	var req webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	if errs := utils.Validate(&req); errs != nil {
+		utils.SendValidationError(w, r, errs)
+		return
+	}
+
+	current, err := c.webhookService.GetWebhookByID(id)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdateWebhookFailed)
+		return
+	}
+
+	hook := models.Webhook{URL: req.URL, Secret: req.Secret, Events: req.Events, Active: current.Active}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+	if err := c.webhookService.UpdateWebhook(id, &hook); err != nil {
+		utils.SendError(w, r, err, i18n.MsgUpdateWebhookFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgWebhookUpdated, nil)
+}
+
+// DeleteWebhook 删除 Webhook
+func (c *WebhookController) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidWebhookID)
+		return
+	}
+
+	if err := c.webhookService.DeleteWebhook(id); err != nil {
+		utils.SendError(w, r, err, i18n.MsgDeleteWebhookFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusOK, i18n.MsgWebhookDeleted, nil)
+}
+
+// GetDeliveries 获取 Webhook 的投递记录，支持 limit、offset 参数
+func (c *WebhookController) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidWebhookID)
+		return
+	}
+
+	limit, offset, ok := parsePagination(r)
+	if !ok {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidRequest)
+		return
+	}
+
+	deliveries, err := c.webhookService.GetDeliveries(id, limit, offset)
+	if err != nil {
+		utils.SendError(w, r, err, i18n.MsgGetDeliveriesFailed)
+		return
+	}
+
+	sendResource(w, r, http.StatusOK, i18n.MsgSuccess, serializers.WebhookDeliveries(deliveries))
+}
+
+// Redeliver 重新投递一条记录
+func (c *WebhookController) Redeliver(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidWebhookID)
+		return
+	}
+	deliveryID, err := strconv.ParseInt(vars["deliveryId"], 10, 64)
+	if err != nil {
+		utils.SendErrorResponse(w, r, http.StatusBadRequest, i18n.MsgInvalidDeliveryID)
+		return
+	}
+
+	if err := c.webhookService.Redeliver(id, deliveryID); err != nil {
+		utils.SendError(w, r, err, i18n.MsgRedeliverFailed)
+		return
+	}
+
+	utils.SendResponse(w, r, http.StatusAccepted, i18n.MsgRedeliveryQueued, nil)
+}
@@ -0,0 +1,87 @@
+package events
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// 领域事件类型
+const (
+	ArticleCreated = "article.created"
+	ArticleUpdated = "article.updated"
+	ArticleDeleted = "article.deleted"
+	CommentCreated = "comment.created"
+	CommentDeleted = "comment.deleted"
+	UserRegistered = "user.registered"
+)
+
+// Types 所有领域事件类型，外部订阅（如 Webhook）只能订阅这些事件
+var Types = []string{
+	ArticleCreated,
+	ArticleUpdated,
+	ArticleDeleted,
+	CommentCreated,
+	CommentDeleted,
+	UserRegistered,
+}
+
+// Known 判断是否为已定义的事件类型
+func Known(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event 业务操作完成后发布的领域事件
+type Event struct {
+	Type       string
+	OccurredAt time.Time
+	Data       interface{}
+}
+
+// Handler 事件处理函数，在发布者的 goroutine 中同步调用，耗时的工作应写入队列或异步执行
+type Handler func(Event)
+
+// Bus 进程内事件总线
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+// NewBus 创建事件总线
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe 注册事件处理函数，接收所有类型的事件
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish 依次调用所有处理函数。处理函数的 panic 会被记录，不影响其他处理函数和发布者
+func (b *Bus) Publish(eventType string, data interface{}) {
+	event := Event{Type: eventType, OccurredAt: time.Now(), Data: data}
+
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		b.dispatch(handler, event)
+	}
+}
+
+func (b *Bus) dispatch(handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("event handler panicked", "event", event.Type, "panic", r)
+		}
+	}()
+	handler(event)
+}
@@ -0,0 +1,195 @@
+package i18n
+
+// enMessages English message catalog
+var enMessages = map[string]string{
+	// 通用
+	MsgSuccess:            "Success",
+	MsgInvalidRequest:     "Invalid request body",
+	MsgInvalidForm:        "Invalid form data",
+	MsgValidationFailed:   "Request validation failed",
+	MsgServiceUnavailable: "Service not initialized",
+	MsgInternalError:      "Internal server error",
+	MsgTooManyRequests:    "Too many requests, please try again later",
+
+	// 认证与权限
+	MsgMissingCredentials:  "Authentication credentials were not provided",
+	MsgInvalidToken:        "Invalid authentication credentials",
+	MsgNotLoggedIn:         "Not logged in",
+	MsgPermissionDenied:    "Permission denied",
+	MsgUserInfoUnavailable: "Unable to determine the current user",
+	MsgUserRoleUnavailable: "Unable to determine the user's role",
+	MsgInvalidCredentials:  "Incorrect username or password",
+	MsgTokenFailed:         "Failed to generate token",
+	MsgAccountLocked:       "Too many failed login attempts, the account is temporarily locked",
+
+	// 参数
+	MsgInvalidArticleID:  "Invalid article ID",
+	MsgInvalidCategoryID: "Invalid category ID",
+	MsgInvalidCommentID:  "Invalid comment ID",
+	MsgInvalidUserID:     "Invalid user ID",
+
+	// 文章
+	MsgArticleNotFound:     "Article not found",
+	MsgArticleCreated:      "Article created",
+	MsgArticleUpdated:      "Article updated",
+	MsgArticleDeleted:      "Article deleted",
+	MsgGetArticlesFailed:   "Failed to load articles",
+	MsgGetArticleFailed:    "Failed to load article",
+	MsgCreateArticleFailed: "Failed to create article",
+	MsgUpdateArticleFailed: "Failed to update article",
+	MsgDeleteArticleFailed: "Failed to delete article",
+
+	// 分类
+	MsgCategoryNotFound:     "Category not found",
+	MsgCategoryExists:       "Category name already exists",
+	MsgCategoryCreated:      "Category created",
+	MsgCategoryUpdated:      "Category updated",
+	MsgCategoryDeleted:      "Category deleted",
+	MsgGetCategoriesFailed:  "Failed to load categories",
+	MsgGetCategoryFailed:    "Failed to load category",
+	MsgCreateCategoryFailed: "Failed to create category",
+	MsgUpdateCategoryFailed: "Failed to update category",
+	MsgDeleteCategoryFailed: "Failed to delete category",
+
+	// 评论
+	MsgCommentNotFound:      "Comment not found",
+	MsgCommentCreated:       "Comment created",
+	MsgCommentUpdated:       "Comment updated",
+	MsgParentCommentInvalid: "The comment being replied to does not exist or belongs to another article",
+	MsgCommentDeleted:       "Comment deleted",
+	MsgGetCommentsFailed:    "Failed to load comments",
+	MsgCreateCommentFailed:  "Failed to create comment",
+	MsgUpdateCommentFailed:  "Failed to update comment",
+	MsgDeleteCommentFailed:  "Failed to delete comment",
+
+	// 用户
+	MsgUserNotFound:           "User not found",
+	MsgUsernameTaken:          "Username already taken",
+	MsgRegistered:             "Registration successful",
+	MsgLoggedIn:               "Login successful",
+	MsgUserUpdated:            "User updated",
+	MsgUserDeleted:            "User deleted",
+	MsgUserRoleUpdated:        "User role updated",
+	MsgBackgroundUpdated:      "Background image updated",
+	MsgRegisterFailed:         "Registration failed",
+	MsgLoginFailed:            "Login failed",
+	MsgGetUserFailed:          "Failed to load user",
+	MsgGetUsersFailed:         "Failed to load users",
+	MsgUpdateUserFailed:       "Failed to update user",
+	MsgDeleteUserFailed:       "Failed to delete user",
+	MsgUpdateUserRoleFailed:   "Failed to update user role",
+	MsgUpdateBackgroundFailed: "Failed to update background image",
+	MsgProfileUpdated:         "Profile updated",
+	MsgUpdateProfileFailed:    "Failed to update profile",
+	MsgGetAuthorFailed:        "Failed to get author",
+	MsgUsePasswordEndpoint:    "Use the change password endpoint, which requires the current password",
+	MsgUserStatusUpdated:      "User status updated",
+	MsgUpdateUserStatusFailed: "Failed to update user status",
+	MsgCannotChangeOwnStatus:  "You cannot change the status of your own account",
+	MsgStatusUntilInvalid:     "An expiry can only be set for suspensions and must be in the future",
+	MsgAccountSuspended:       "Your account has been suspended",
+	MsgAccountBanned:          "Your account has been banned",
+
+	// 邮箱验证与密码
+	MsgEmailVerified:          "Email verified",
+	MsgEmailAlreadyVerified:   "Email is already verified",
+	MsgVerificationSent:       "Verification email sent",
+	MsgInvalidOrExpiredToken:  "The link is invalid or has expired",
+	MsgPasswordResetRequested: "If the email is registered, a password reset email has been sent",
+	MsgPasswordReset:          "Password has been reset, please log in with the new password",
+	MsgPasswordChanged:        "Password changed",
+	MsgOldPasswordIncorrect:   "The current password is incorrect",
+	MsgVerifyEmailFailed:      "Failed to verify email",
+	MsgSendVerificationFailed: "Failed to send verification email",
+	MsgForgotPasswordFailed:   "Failed to request password reset",
+	MsgResetPasswordFailed:    "Failed to reset password",
+	MsgChangePasswordFailed:   "Failed to change password",
+
+	// 回收站
+	MsgArticleRestored:      "Article restored",
+	MsgCommentRestored:      "Comment restored",
+	MsgRestoreArticleFailed: "Failed to restore article",
+	MsgRestoreCommentFailed: "Failed to restore comment",
+	MsgRestoreNotAllowed:    "This item was removed by an administrator and can only be restored by one",
+	MsgGetTrashFailed:       "Failed to get trash",
+
+	// 反应
+	MsgReactionToggled: "Reaction updated",
+	MsgReactFailed:     "Failed to update reaction",
+
+	// 收藏与阅读列表
+	MsgBookmarked:              "Article bookmarked",
+	MsgBookmarkRemoved:         "Bookmark removed",
+	MsgBookmarkFailed:          "Failed to update bookmark",
+	MsgGetBookmarksFailed:      "Failed to get bookmarks",
+	MsgReadingListNotFound:     "Reading list not found",
+	MsgReadingListExists:       "A reading list with this name already exists",
+	MsgInvalidReadingListID:    "Invalid reading list ID",
+	MsgReadingListCreated:      "Reading list created",
+	MsgReadingListUpdated:      "Reading list updated",
+	MsgReadingListDeleted:      "Reading list deleted",
+	MsgReadingListOrderInvalid: "must list every article in the reading list exactly once",
+	MsgGetReadingListsFailed:   "Failed to get reading lists",
+	MsgCreateReadingListFailed: "Failed to create reading list",
+	MsgUpdateReadingListFailed: "Failed to update reading list",
+	MsgDeleteReadingListFailed: "Failed to delete reading list",
+
+	// 关注与动态
+	MsgFollowed:         "Followed",
+	MsgUnfollowed:       "Unfollowed",
+	MsgFollowFailed:     "Failed to update follow",
+	MsgCannotFollowSelf: "You cannot follow yourself",
+	MsgInvalidCursor:    "Invalid pagination cursor",
+	MsgGetFeedFailed:    "Failed to get feed",
+
+	// 通知
+	MsgPreferencesUpdated:      "Notification preferences updated",
+	MsgGetPreferencesFailed:    "Failed to get notification preferences",
+	MsgUpdatePreferencesFailed: "Failed to update notification preferences",
+	MsgNotificationNotFound:    "Notification not found",
+	MsgInvalidNotificationID:   "Invalid notification ID",
+	MsgNotificationRead:        "Marked as read",
+	MsgGetNotificationsFailed:  "Failed to get notifications",
+	MsgMarkReadFailed:          "Failed to mark notifications as read",
+
+	// Webhook
+	MsgWebhookCreated:      "Webhook created",
+	MsgWebhookUpdated:      "Webhook updated",
+	MsgWebhookDeleted:      "Webhook deleted",
+	MsgWebhookNotFound:     "Webhook not found",
+	MsgInvalidWebhookID:    "Invalid webhook ID",
+	MsgUnknownWebhookEvent: "contains an unknown event type",
+	MsgGetWebhooksFailed:   "Failed to get webhooks",
+	MsgCreateWebhookFailed: "Failed to create webhook",
+	MsgUpdateWebhookFailed: "Failed to update webhook",
+	MsgDeleteWebhookFailed: "Failed to delete webhook",
+	MsgDeliveryNotFound:    "Webhook delivery not found",
+	MsgInvalidDeliveryID:   "Invalid webhook delivery ID",
+	MsgGetDeliveriesFailed: "Failed to get webhook deliveries",
+	MsgRedeliveryQueued:    "Redelivery queued",
+	MsgRedeliverFailed:     "Failed to queue redelivery",
+
+	// 图片上传
+	MsgUploaded:             "Upload successful",
+	MsgUnsupportedImage:     "Unsupported image format",
+	MsgImageTooLarge:        "Image file or dimensions exceed the limit",
+	MsgInvalidImage:         "Invalid image",
+	MsgImageRequired:        "An image is required",
+	MsgBackgroundRequired:   "A background image is required",
+	MsgSaveImageFailed:      "Failed to save image",
+	MsgSaveAvatarFailed:     "Failed to save avatar",
+	MsgSaveBackgroundFailed: "Failed to save background image",
+
+	// 字段校验
+	MsgFieldRequired:  "is required",
+	MsgFieldMinLength: "must be at least %d characters",
+	MsgFieldMaxLength: "must be at most %d characters",
+	MsgFieldMaxBytes:  "must be at most %d bytes",
+	MsgFieldMin:       "must be at least %d",
+	MsgFieldMax:       "must be at most %d",
+	MsgFieldEmail:     "must be a valid email address",
+	MsgFieldUsername:  "may contain only letters, digits, underscores or Chinese characters and must be 3-32 characters long",
+	MsgFieldPassword:  "must be at least 8 characters and contain both letters and digits",
+	MsgFieldOneOf:     "must be one of: %s",
+	MsgFieldURL:       "must be a valid http or https URL",
+}
@@ -0,0 +1,105 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale 语言标识
+type Locale string
+
+const (
+	ZhCN Locale = "zh-CN"
+	En   Locale = "en"
+
+	// DefaultLocale 无法协商出支持的语言时使用
+	DefaultLocale = ZhCN
+)
+
+// catalogs 各语言的消息目录，键为消息码
+var catalogs = map[Locale]map[string]string{
+	ZhCN: zhCNMessages,
+	En:   enMessages,
+}
+
+// T 返回消息码在指定语言下的文本，args 用于格式化占位符。
+// 目标语言缺少该消息时回退到默认语言，仍缺失则返回消息码本身
+func T(locale Locale, code string, args ...interface{}) string {
+	msg, ok := catalogs[locale][code]
+	if !ok {
+		msg, ok = catalogs[DefaultLocale][code]
+	}
+	if !ok {
+		return code
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Parse 将语言标签（如 en-US、zh、zh-Hans-CN）匹配到支持的语言
+func Parse(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	switch {
+	case tag == "":
+		return "", false
+	case tag == "zh" || strings.HasPrefix(tag, "zh-"):
+		return ZhCN, true
+	case tag == "en" || strings.HasPrefix(tag, "en-"):
+		return En, true
+	}
+	return "", false
+}
+
+// Negotiate 按 Accept-Language 头的权重选出最合适的支持语言
+func Negotiate(acceptLanguage string) Locale {
+	type candidate struct {
+		locale  Locale
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		locale, ok := Parse(tag)
+		if !ok {
+			continue
+		}
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if v, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = v
+			}
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{locale, quality})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLocale
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].quality > candidates[b].quality
+	})
+	return candidates[0].locale
+}
+
+type contextKey struct{}
+
+// WithLocale 将语言写入请求上下文
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext 读取请求上下文中的语言，未设置时返回默认语言
+func FromContext(ctx context.Context) Locale {
+	if locale, ok := ctx.Value(contextKey{}).(Locale); ok {
+		return locale
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		tag    string
+		want   Locale
+		wantOK bool
+	}{
+		{"", "", false},
+		{"  ", "", false},
+		{"zh", ZhCN, true},
+		{"zh-TW", ZhCN, true},
+		{"ZH-Hans-CN", ZhCN, true},
+		{" en ", En, true},
+		{"en-US", En, true},
+		{"eng", "", false},
+		{"zha", "", false},
+		{"fr", "", false},
+		{"*", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.tag)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Parse(%q) = %q, %v, want %q, %v", tt.tag, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   Locale
+	}{
+		{"empty header", "", DefaultLocale},
+		{"single", "en-US", En},
+		{"first wins", "en-US,en;q=0.9,zh;q=0.8", En},
+		{"unsupported skipped", "fr, en;q=0.5", En},
+		{"higher quality wins", "zh;q=0.3, en;q=0.8", En},
+		{"implicit quality is 1", "en;q=0.9, zh", ZhCN},
+		{"equal quality keeps order", "en;q=0.5, zh;q=0.5", En},
+		{"zero quality rejected", "en;q=0", DefaultLocale},
+		{"invalid quality treated as 1", "zh;q=0.5, en;q=abc", En},
+		{"only unsupported", "fr-FR, de;q=0.9, *;q=0.1", DefaultLocale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Negotiate(tt.header); got != tt.want {
+				t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T(En, MsgFieldMaxBytes, 10); got != "must be at most 10 bytes" {
+		t.Errorf("T(en) = %q", got)
+	}
+	if got := T(Locale("fr"), MsgFieldMaxBytes, 10); got != "长度不能超过10字节" {
+		t.Errorf("T(fr) = %q, want default locale", got)
+	}
+	if got := T(En, "NO_SUCH_CODE"); got != "NO_SUCH_CODE" {
+		t.Errorf("T(unknown) = %q", got)
+	}
+}
+
+// TestCatalogsComplete 每种语言的消息目录都应包含全部消息码
+func TestCatalogsComplete(t *testing.T) {
+	for locale, messages := range catalogs {
+		for other, otherMessages := range catalogs {
+			for code := range otherMessages {
+				if _, ok := messages[code]; !ok {
+					t.Errorf("%s: missing %s (present in %s)", locale, code, other)
+				}
+			}
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != DefaultLocale {
+		t.Errorf("FromContext(empty) = %q", got)
+	}
+	if got := FromContext(WithLocale(context.Background(), En)); got != En {
+		t.Errorf("FromContext = %q, want en", got)
+	}
+}
@@ -0,0 +1,195 @@
+package i18n
+
+// 消息码，作为 APIResponse.error_code 返回给客户端，发布后不应修改
+const (
+	// 通用
+	MsgSuccess            = "SUCCESS"
+	MsgInvalidRequest     = "INVALID_REQUEST"
+	MsgInvalidForm        = "INVALID_FORM"
+	MsgValidationFailed   = "VALIDATION_FAILED"
+	MsgServiceUnavailable = "SERVICE_NOT_INITIALIZED"
+	MsgInternalError      = "INTERNAL_ERROR"
+	MsgTooManyRequests    = "TOO_MANY_REQUESTS"
+
+	// 认证与权限
+	MsgMissingCredentials  = "MISSING_CREDENTIALS"
+	MsgInvalidToken        = "INVALID_TOKEN"
+	MsgNotLoggedIn         = "NOT_LOGGED_IN"
+	MsgPermissionDenied    = "PERMISSION_DENIED"
+	MsgUserInfoUnavailable = "USER_INFO_UNAVAILABLE"
+	MsgUserRoleUnavailable = "USER_ROLE_UNAVAILABLE"
+	MsgInvalidCredentials  = "INVALID_CREDENTIALS"
+	MsgTokenFailed         = "TOKEN_GENERATION_FAILED"
+	MsgAccountLocked       = "ACCOUNT_LOCKED"
+
+	// 参数
+	MsgInvalidArticleID  = "INVALID_ARTICLE_ID"
+	MsgInvalidCategoryID = "INVALID_CATEGORY_ID"
+	MsgInvalidCommentID  = "INVALID_COMMENT_ID"
+	MsgInvalidUserID     = "INVALID_USER_ID"
+
+	// 文章
+	MsgArticleNotFound     = "ARTICLE_NOT_FOUND"
+	MsgArticleCreated      = "ARTICLE_CREATED"
+	MsgArticleUpdated      = "ARTICLE_UPDATED"
+	MsgArticleDeleted      = "ARTICLE_DELETED"
+	MsgGetArticlesFailed   = "GET_ARTICLES_FAILED"
+	MsgGetArticleFailed    = "GET_ARTICLE_FAILED"
+	MsgCreateArticleFailed = "CREATE_ARTICLE_FAILED"
+	MsgUpdateArticleFailed = "UPDATE_ARTICLE_FAILED"
+	MsgDeleteArticleFailed = "DELETE_ARTICLE_FAILED"
+
+	// 分类
+	MsgCategoryNotFound     = "CATEGORY_NOT_FOUND"
+	MsgCategoryExists       = "CATEGORY_EXISTS"
+	MsgCategoryCreated      = "CATEGORY_CREATED"
+	MsgCategoryUpdated      = "CATEGORY_UPDATED"
+	MsgCategoryDeleted      = "CATEGORY_DELETED"
+	MsgGetCategoriesFailed  = "GET_CATEGORIES_FAILED"
+	MsgGetCategoryFailed    = "GET_CATEGORY_FAILED"
+	MsgCreateCategoryFailed = "CREATE_CATEGORY_FAILED"
+	MsgUpdateCategoryFailed = "UPDATE_CATEGORY_FAILED"
+	MsgDeleteCategoryFailed = "DELETE_CATEGORY_FAILED"
+
+	// 评论
+	MsgCommentNotFound      = "COMMENT_NOT_FOUND"
+	MsgCommentCreated       = "COMMENT_CREATED"
+	MsgCommentUpdated       = "COMMENT_UPDATED"
+	MsgCommentDeleted       = "COMMENT_DELETED"
+	MsgParentCommentInvalid = "PARENT_COMMENT_INVALID"
+	MsgGetCommentsFailed    = "GET_COMMENTS_FAILED"
+	MsgCreateCommentFailed  = "CREATE_COMMENT_FAILED"
+	MsgUpdateCommentFailed  = "UPDATE_COMMENT_FAILED"
+	MsgDeleteCommentFailed  = "DELETE_COMMENT_FAILED"
+
+	// 用户
+	MsgUserNotFound           = "USER_NOT_FOUND"
+	MsgUsernameTaken          = "USERNAME_TAKEN"
+	MsgRegistered             = "REGISTERED"
+	MsgLoggedIn               = "LOGGED_IN"
+	MsgUserUpdated            = "USER_UPDATED"
+	MsgUserDeleted            = "USER_DELETED"
+	MsgUserRoleUpdated        = "USER_ROLE_UPDATED"
+	MsgBackgroundUpdated      = "BACKGROUND_UPDATED"
+	MsgRegisterFailed         = "REGISTER_FAILED"
+	MsgLoginFailed            = "LOGIN_FAILED"
+	MsgGetUserFailed          = "GET_USER_FAILED"
+	MsgGetUsersFailed         = "GET_USERS_FAILED"
+	MsgUpdateUserFailed       = "UPDATE_USER_FAILED"
+	MsgDeleteUserFailed       = "DELETE_USER_FAILED"
+	MsgUpdateUserRoleFailed   = "UPDATE_USER_ROLE_FAILED"
+	MsgUpdateBackgroundFailed = "UPDATE_BACKGROUND_FAILED"
+	MsgProfileUpdated         = "PROFILE_UPDATED"
+	MsgUpdateProfileFailed    = "UPDATE_PROFILE_FAILED"
+	MsgGetAuthorFailed        = "GET_AUTHOR_FAILED"
+	MsgUsePasswordEndpoint    = "USE_PASSWORD_ENDPOINT"
+	MsgUserStatusUpdated      = "USER_STATUS_UPDATED"
+	MsgUpdateUserStatusFailed = "UPDATE_USER_STATUS_FAILED"
+	MsgCannotChangeOwnStatus  = "CANNOT_CHANGE_OWN_STATUS"
+	MsgStatusUntilInvalid     = "STATUS_UNTIL_INVALID"
+	MsgAccountSuspended       = "ACCOUNT_SUSPENDED"
+	MsgAccountBanned          = "ACCOUNT_BANNED"
+
+	// 邮箱验证与密码
+	MsgEmailVerified          = "EMAIL_VERIFIED"
+	MsgEmailAlreadyVerified   = "EMAIL_ALREADY_VERIFIED"
+	MsgVerificationSent       = "VERIFICATION_EMAIL_SENT"
+	MsgInvalidOrExpiredToken  = "INVALID_OR_EXPIRED_TOKEN"
+	MsgPasswordResetRequested = "PASSWORD_RESET_REQUESTED"
+	MsgPasswordReset          = "PASSWORD_RESET"
+	MsgPasswordChanged        = "PASSWORD_CHANGED"
+	MsgOldPasswordIncorrect   = "OLD_PASSWORD_INCORRECT"
+	MsgVerifyEmailFailed      = "VERIFY_EMAIL_FAILED"
+	MsgSendVerificationFailed = "SEND_VERIFICATION_FAILED"
+	MsgForgotPasswordFailed   = "FORGOT_PASSWORD_FAILED"
+	MsgResetPasswordFailed    = "RESET_PASSWORD_FAILED"
+	MsgChangePasswordFailed   = "CHANGE_PASSWORD_FAILED"
+
+	// 回收站
+	MsgArticleRestored      = "ARTICLE_RESTORED"
+	MsgCommentRestored      = "COMMENT_RESTORED"
+	MsgRestoreArticleFailed = "RESTORE_ARTICLE_FAILED"
+	MsgRestoreCommentFailed = "RESTORE_COMMENT_FAILED"
+	MsgRestoreNotAllowed    = "RESTORE_NOT_ALLOWED"
+	MsgGetTrashFailed       = "GET_TRASH_FAILED"
+
+	// 反应
+	MsgReactionToggled = "REACTION_TOGGLED"
+	MsgReactFailed     = "REACTION_FAILED"
+
+	// 收藏与阅读列表
+	MsgBookmarked              = "BOOKMARKED"
+	MsgBookmarkRemoved         = "BOOKMARK_REMOVED"
+	MsgBookmarkFailed          = "BOOKMARK_FAILED"
+	MsgGetBookmarksFailed      = "GET_BOOKMARKS_FAILED"
+	MsgReadingListNotFound     = "READING_LIST_NOT_FOUND"
+	MsgReadingListExists       = "READING_LIST_EXISTS"
+	MsgInvalidReadingListID    = "INVALID_READING_LIST_ID"
+	MsgReadingListCreated      = "READING_LIST_CREATED"
+	MsgReadingListUpdated      = "READING_LIST_UPDATED"
+	MsgReadingListDeleted      = "READING_LIST_DELETED"
+	MsgReadingListOrderInvalid = "READING_LIST_ORDER_INVALID"
+	MsgGetReadingListsFailed   = "GET_READING_LISTS_FAILED"
+	MsgCreateReadingListFailed = "CREATE_READING_LIST_FAILED"
+	MsgUpdateReadingListFailed = "UPDATE_READING_LIST_FAILED"
+	MsgDeleteReadingListFailed = "DELETE_READING_LIST_FAILED"
+
+	// 关注与动态
+	MsgFollowed         = "FOLLOWED"
+	MsgUnfollowed       = "UNFOLLOWED"
+	MsgFollowFailed     = "FOLLOW_FAILED"
+	MsgCannotFollowSelf = "CANNOT_FOLLOW_SELF"
+	MsgInvalidCursor    = "INVALID_CURSOR"
+	MsgGetFeedFailed    = "GET_FEED_FAILED"
+
+	// 通知
+	MsgPreferencesUpdated      = "NOTIFICATION_PREFERENCES_UPDATED"
+	MsgGetPreferencesFailed    = "GET_NOTIFICATION_PREFERENCES_FAILED"
+	MsgUpdatePreferencesFailed = "UPDATE_NOTIFICATION_PREFERENCES_FAILED"
+	MsgNotificationNotFound    = "NOTIFICATION_NOT_FOUND"
+	MsgInvalidNotificationID   = "INVALID_NOTIFICATION_ID"
+	MsgNotificationRead        = "NOTIFICATION_READ"
+	MsgGetNotificationsFailed  = "GET_NOTIFICATIONS_FAILED"
+	MsgMarkReadFailed          = "MARK_NOTIFICATION_READ_FAILED"
+
+	// Webhook
+	MsgWebhookCreated      = "WEBHOOK_CREATED"
+	MsgWebhookUpdated      = "WEBHOOK_UPDATED"
+	MsgWebhookDeleted      = "WEBHOOK_DELETED"
+	MsgWebhookNotFound     = "WEBHOOK_NOT_FOUND"
+	MsgInvalidWebhookID    = "INVALID_WEBHOOK_ID"
+	MsgUnknownWebhookEvent = "UNKNOWN_WEBHOOK_EVENT"
+	MsgGetWebhooksFailed   = "GET_WEBHOOKS_FAILED"
+	MsgCreateWebhookFailed = "CREATE_WEBHOOK_FAILED"
+	MsgUpdateWebhookFailed = "UPDATE_WEBHOOK_FAILED"
+	MsgDeleteWebhookFailed = "DELETE_WEBHOOK_FAILED"
+	MsgDeliveryNotFound    = "WEBHOOK_DELIVERY_NOT_FOUND"
+	MsgInvalidDeliveryID   = "INVALID_WEBHOOK_DELIVERY_ID"
+	MsgGetDeliveriesFailed = "GET_WEBHOOK_DELIVERIES_FAILED"
+	MsgRedeliveryQueued    = "WEBHOOK_REDELIVERY_QUEUED"
+	MsgRedeliverFailed     = "WEBHOOK_REDELIVER_FAILED"
+
+	// 图片上传
+	MsgUploaded             = "UPLOADED"
+	MsgUnsupportedImage     = "UNSUPPORTED_IMAGE"
+	MsgImageTooLarge        = "IMAGE_TOO_LARGE"
+	MsgInvalidImage         = "INVALID_IMAGE"
+	MsgImageRequired        = "IMAGE_REQUIRED"
+	MsgBackgroundRequired   = "BACKGROUND_REQUIRED"
+	MsgSaveImageFailed      = "SAVE_IMAGE_FAILED"
+	MsgSaveAvatarFailed     = "SAVE_AVATAR_FAILED"
+	MsgSaveBackgroundFailed = "SAVE_BACKGROUND_FAILED"
+
+	// 字段校验
+	MsgFieldRequired  = "FIELD_REQUIRED"
+	MsgFieldMinLength = "FIELD_MIN_LENGTH"
+	MsgFieldMaxLength = "FIELD_MAX_LENGTH"
+	MsgFieldMaxBytes  = "FIELD_MAX_BYTES"
+	MsgFieldMin       = "FIELD_MIN"
+	MsgFieldMax       = "FIELD_MAX"
+	MsgFieldEmail     = "FIELD_EMAIL"
+	MsgFieldUsername  = "FIELD_USERNAME"
+	MsgFieldPassword  = "FIELD_PASSWORD"
+	MsgFieldOneOf     = "FIELD_ONE_OF"
+	MsgFieldURL       = "FIELD_URL"
+)
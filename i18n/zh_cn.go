@@ -0,0 +1,195 @@
+package i18n
+
+// zhCNMessages 简体中文消息目录
+var zhCNMessages = map[string]string{
+	// 通用
+	MsgSuccess:            "成功",
+	MsgInvalidRequest:     "无效的请求数据",
+	MsgInvalidForm:        "无效的表单数据",
+	MsgValidationFailed:   "请求参数校验失败",
+	MsgServiceUnavailable: "服务未初始化",
+	MsgInternalError:      "服务器内部错误",
+	MsgTooManyRequests:    "请求过于频繁，请稍后再试",
+
+	// 认证与权限
+	MsgMissingCredentials:  "未提供认证信息",
+	MsgInvalidToken:        "无效的认证信息",
+	MsgNotLoggedIn:         "未登录",
+	MsgPermissionDenied:    "权限不足",
+	MsgUserInfoUnavailable: "无法获取用户信息",
+	MsgUserRoleUnavailable: "无法获取用户角色",
+	MsgInvalidCredentials:  "用户名或密码错误",
+	MsgTokenFailed:         "生成token失败",
+	MsgAccountLocked:       "登录失败次数过多，账号已临时锁定，请稍后再试",
+
+	// 参数
+	MsgInvalidArticleID:  "无效的文章ID",
+	MsgInvalidCategoryID: "无效的分类ID",
+	MsgInvalidCommentID:  "无效的评论ID",
+	MsgInvalidUserID:     "无效的用户ID",
+
+	// 文章
+	MsgArticleNotFound:     "文章不存在",
+	MsgArticleCreated:      "文章创建成功",
+	MsgArticleUpdated:      "文章更新成功",
+	MsgArticleDeleted:      "文章删除成功",
+	MsgGetArticlesFailed:   "获取文章列表失败",
+	MsgGetArticleFailed:    "获取文章失败",
+	MsgCreateArticleFailed: "创建文章失败",
+	MsgUpdateArticleFailed: "更新文章失败",
+	MsgDeleteArticleFailed: "删除文章失败",
+
+	// 分类
+	MsgCategoryNotFound:     "分类不存在",
+	MsgCategoryExists:       "分类名称已存在",
+	MsgCategoryCreated:      "分类创建成功",
+	MsgCategoryUpdated:      "分类更新成功",
+	MsgCategoryDeleted:      "分类删除成功",
+	MsgGetCategoriesFailed:  "获取分类列表失败",
+	MsgGetCategoryFailed:    "获取分类失败",
+	MsgCreateCategoryFailed: "创建分类失败",
+	MsgUpdateCategoryFailed: "更新分类失败",
+	MsgDeleteCategoryFailed: "删除分类失败",
+
+	// 评论
+	MsgCommentNotFound:      "评论不存在",
+	MsgCommentCreated:       "评论创建成功",
+	MsgCommentUpdated:       "评论更新成功",
+	MsgParentCommentInvalid: "回复的评论不存在或不属于该文章",
+	MsgCommentDeleted:       "评论删除成功",
+	MsgGetCommentsFailed:    "获取评论列表失败",
+	MsgCreateCommentFailed:  "创建评论失败",
+	MsgUpdateCommentFailed:  "更新评论失败",
+	MsgDeleteCommentFailed:  "删除评论失败",
+
+	// 用户
+	MsgUserNotFound:           "用户不存在",
+	MsgUsernameTaken:          "用户名已存在",
+	MsgRegistered:             "注册成功",
+	MsgLoggedIn:               "登录成功",
+	MsgUserUpdated:            "用户信息更新成功",
+	MsgUserDeleted:            "用户删除成功",
+	MsgUserRoleUpdated:        "用户角色更新成功",
+	MsgBackgroundUpdated:      "用户背景图更新成功",
+	MsgRegisterFailed:         "注册失败",
+	MsgLoginFailed:            "登录失败",
+	MsgGetUserFailed:          "获取用户信息失败",
+	MsgGetUsersFailed:         "获取用户列表失败",
+	MsgUpdateUserFailed:       "更新用户信息失败",
+	MsgDeleteUserFailed:       "删除用户失败",
+	MsgUpdateUserRoleFailed:   "更新用户角色失败",
+	MsgUpdateBackgroundFailed: "更新用户背景图失败",
+	MsgProfileUpdated:         "个人资料更新成功",
+	MsgUpdateProfileFailed:    "更新个人资料失败",
+	MsgGetAuthorFailed:        "获取作者信息失败",
+	MsgUsePasswordEndpoint:    "请通过修改密码接口修改密码，需要提供原密码",
+	MsgUserStatusUpdated:      "用户状态更新成功",
+	MsgUpdateUserStatusFailed: "更新用户状态失败",
+	MsgCannotChangeOwnStatus:  "不能修改自己的账号状态",
+	MsgStatusUntilInvalid:     "截止时间只能用于暂停，且必须晚于当前时间",
+	MsgAccountSuspended:       "账号已被暂停使用",
+	MsgAccountBanned:          "账号已被封禁",
+
+	// 邮箱验证与密码
+	MsgEmailVerified:          "邮箱验证成功",
+	MsgEmailAlreadyVerified:   "邮箱已验证",
+	MsgVerificationSent:       "验证邮件已发送",
+	MsgInvalidOrExpiredToken:  "链接无效或已过期",
+	MsgPasswordResetRequested: "如果该邮箱已注册，重置密码的邮件已发送",
+	MsgPasswordReset:          "密码已重置，请使用新密码登录",
+	MsgPasswordChanged:        "密码修改成功",
+	MsgOldPasswordIncorrect:   "原密码不正确",
+	MsgVerifyEmailFailed:      "邮箱验证失败",
+	MsgSendVerificationFailed: "发送验证邮件失败",
+	MsgForgotPasswordFailed:   "申请重置密码失败",
+	MsgResetPasswordFailed:    "重置密码失败",
+	MsgChangePasswordFailed:   "修改密码失败",
+
+	// 回收站
+	MsgArticleRestored:      "文章已恢复",
+	MsgCommentRestored:      "评论已恢复",
+	MsgRestoreArticleFailed: "恢复文章失败",
+	MsgRestoreCommentFailed: "恢复评论失败",
+	MsgRestoreNotAllowed:    "该内容由管理员删除，只有管理员可以恢复",
+	MsgGetTrashFailed:       "获取回收站失败",
+
+	// 反应
+	MsgReactionToggled: "反应已更新",
+	MsgReactFailed:     "更新反应失败",
+
+	// 收藏与阅读列表
+	MsgBookmarked:              "已收藏",
+	MsgBookmarkRemoved:         "已取消收藏",
+	MsgBookmarkFailed:          "收藏操作失败",
+	MsgGetBookmarksFailed:      "获取收藏失败",
+	MsgReadingListNotFound:     "阅读列表不存在",
+	MsgReadingListExists:       "已存在同名的阅读列表",
+	MsgInvalidReadingListID:    "无效的阅读列表ID",
+	MsgReadingListCreated:      "阅读列表创建成功",
+	MsgReadingListUpdated:      "阅读列表更新成功",
+	MsgReadingListDeleted:      "阅读列表删除成功",
+	MsgReadingListOrderInvalid: "必须包含列表中的全部文章且不能重复",
+	MsgGetReadingListsFailed:   "获取阅读列表失败",
+	MsgCreateReadingListFailed: "创建阅读列表失败",
+	MsgUpdateReadingListFailed: "更新阅读列表失败",
+	MsgDeleteReadingListFailed: "删除阅读列表失败",
+
+	// 关注与动态
+	MsgFollowed:         "关注成功",
+	MsgUnfollowed:       "已取消关注",
+	MsgFollowFailed:     "关注操作失败",
+	MsgCannotFollowSelf: "不能关注自己",
+	MsgInvalidCursor:    "无效的分页游标",
+	MsgGetFeedFailed:    "获取动态失败",
+
+	// 通知
+	MsgPreferencesUpdated:      "通知设置已更新",
+	MsgGetPreferencesFailed:    "获取通知设置失败",
+	MsgUpdatePreferencesFailed: "更新通知设置失败",
+	MsgNotificationNotFound:    "通知不存在",
+	MsgInvalidNotificationID:   "无效的通知ID",
+	MsgNotificationRead:        "已标记为已读",
+	MsgGetNotificationsFailed:  "获取通知失败",
+	MsgMarkReadFailed:          "标记已读失败",
+
+	// Webhook
+	MsgWebhookCreated:      "Webhook创建成功",
+	MsgWebhookUpdated:      "Webhook更新成功",
+	MsgWebhookDeleted:      "Webhook删除成功",
+	MsgWebhookNotFound:     "Webhook不存在",
+	MsgInvalidWebhookID:    "无效的Webhook ID",
+	MsgUnknownWebhookEvent: "包含未知的事件类型",
+	MsgGetWebhooksFailed:   "获取Webhook失败",
+	MsgCreateWebhookFailed: "创建Webhook失败",
+	MsgUpdateWebhookFailed: "更新Webhook失败",
+	MsgDeleteWebhookFailed: "删除Webhook失败",
+	MsgDeliveryNotFound:    "投递记录不存在",
+	MsgInvalidDeliveryID:   "无效的投递记录ID",
+	MsgGetDeliveriesFailed: "获取投递记录失败",
+	MsgRedeliveryQueued:    "已加入重新投递队列",
+	MsgRedeliverFailed:     "重新投递失败",
+
+	// 图片上传
+	MsgUploaded:             "上传成功",
+	MsgUnsupportedImage:     "不支持的图片格式",
+	MsgImageTooLarge:        "图片文件或尺寸超出限制",
+	MsgInvalidImage:         "无效的图片",
+	MsgImageRequired:        "图片为必填项",
+	MsgBackgroundRequired:   "背景图为必填项",
+	MsgSaveImageFailed:      "保存图片失败",
+	MsgSaveAvatarFailed:     "保存头像失败",
+	MsgSaveBackgroundFailed: "保存背景图失败",
+
+	// 字段校验
+	MsgFieldRequired:  "不能为空",
+	MsgFieldMinLength: "长度不能少于%d个字符",
+	MsgFieldMaxLength: "长度不能超过%d个字符",
+	MsgFieldMaxBytes:  "长度不能超过%d字节",
+	MsgFieldMin:       "不能小于%d",
+	MsgFieldMax:       "不能大于%d",
+	MsgFieldEmail:     "邮箱格式不正确",
+	MsgFieldUsername:  "只能包含字母、数字、下划线或汉字，长度为3-32个字符",
+	MsgFieldPassword:  "至少8个字符，且必须同时包含字母和数字",
+	MsgFieldOneOf:     "取值必须为以下之一: %s",
+	MsgFieldURL:       "必须是有效的 http 或 https 地址",
+}
@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer 将邮件保存为 .eml 文件而不真正发送，用于本地开发和测试
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer 创建文件发送器，邮件保存在 dir 目录
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+// Send 实现 Mailer
+func (m *FileMailer) Send(msg Message) error {
+	data, err := build(m.from, msg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	name := time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix) + ".eml"
+	return os.WriteFile(filepath.Join(m.dir, name), data, 0644)
+}
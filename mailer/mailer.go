@@ -0,0 +1,80 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+)
+
+var ErrInvalidHeader = errors.New("邮件头包含非法字符")
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// Message 纯文本邮件
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Config 邮件发送配置
+type Config struct {
+	Backend string // smtp 或 file
+
+	From string
+
+	// SMTP
+	SMTPHost     string
+	SMTPPort     string
+	SMTPSecurity string // starttls（默认）、tls 或 none
+	SMTPUsername string
+	SMTPPassword string
+
+	// 本地文件，用于开发和测试
+	Dir string
+}
+
+// New 根据配置创建邮件发送器，默认将邮件写入本地目录
+func New(cfg Config) (Mailer, error) {
+	switch cfg.Backend {
+	case "", "file":
+		return NewFileMailer(cfg.Dir, cfg.From), nil
+	case "smtp":
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPSecurity, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From)
+	default:
+		return nil, fmt.Errorf("unknown mail backend: %s", cfg.Backend)
+	}
+}
+
+// build 生成 RFC 5322 格式的邮件内容，正文使用 base64 编码以支持中文
+func build(from string, msg Message) ([]byte, error) {
+	for _, h := range []string{from, msg.To, msg.Subject} {
+		if strings.ContainsAny(h, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,126 @@
+package mailer
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// SMTP 连接加密方式
+const (
+	SecurityStartTLS = "starttls" // 服务器支持时升级为TLS（默认，通常为587端口）
+	SecurityTLS      = "tls"      // 直接建立TLS连接（通常为465端口）
+	SecurityNone     = "none"     // 不加密，仅用于本地邮件捕获工具（如 MailHog、Mailpit）
+)
+
+// dialTimeout 连接SMTP服务器的超时时间
+const dialTimeout = 10 * time.Second
+
+// SMTPMailer 通过SMTP服务器发送邮件
+type SMTPMailer struct {
+	host     string
+	addr     string
+	security string
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPMailer 创建SMTP发送器，username 为空时不进行认证
+func NewSMTPMailer(host, port, security, username, password, from string) (*SMTPMailer, error) {
+	if host == "" || from == "" {
+		return nil, errors.New("smtp: host and from are required")
+	}
+	switch security {
+	case "":
+		security = SecurityStartTLS
+	case SecurityStartTLS, SecurityTLS, SecurityNone:
+	default:
+		return nil, fmt.Errorf("smtp: unknown security mode: %s", security)
+	}
+	if port == "" {
+		port = "587"
+		if security == SecurityTLS {
+			port = "465"
+		}
+	}
+
+	return &SMTPMailer{
+		host:     host,
+		addr:     net.JoinHostPort(host, port),
+		security: security,
+		username: username,
+		password: password,
+		from:     from,
+	}, nil
+}
+
+// Send 实现 Mailer
+func (m *SMTPMailer) Send(msg Message) error {
+	data, err := build(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	client, err := m.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if m.security == SecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+				return err
+			}
+		}
+	}
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(m.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// dial 连接SMTP服务器
+func (m *SMTPMailer) dial() (*smtp.Client, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	var conn net.Conn
+	var err error
+	if m.security == SecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", m.addr, &tls.Config{ServerName: m.host})
+	} else {
+		conn, err = dialer.Dial("tcp", m.addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
@@ -1,31 +1,118 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
 	"my_blog/config"
+	"my_blog/metrics"
 	"my_blog/middleware"
 	"my_blog/routes"
+	"my_blog/services"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
 func main() {
+	// 初始化日志，后续初始化过程的日志也使用该格式输出
+	config.InitLogger()
+	logger := config.Logger
+
 	// 初始化数据库连接
 	config.InitDB()
+	metrics.RegisterDB(config.DB, "blog")
+
+	// 初始化上传文件存储
+	config.InitStorage()
+
+	// 初始化邮件发送
+	config.InitMailer()
+
+	// 订阅领域事件：实时推送和 Webhook
+	services.RegisterEventHandlers(logger)
+
+	// 收到 SIGINT/SIGTERM 时开始优雅关闭
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 后台任务：定期清理未被引用的上传文件、发送邮件队列、投递 Webhook、永久删除回收站中过期的内容，
+	// 关闭时随 workerCtx 取消而退出
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	mediaService := services.NewMediaService(logger)
+	services.Go(func() {
+		mediaService.RunGarbageCollector(workerCtx, config.UploadGCInterval, config.UploadGCGracePeriod)
+	})
+	mailQueue := services.NewMailQueue(logger)
+	services.Go(func() {
+		mailQueue.Run(workerCtx, config.MailQueueInterval)
+	})
+	webhookDispatcher := services.NewWebhookDispatcher(logger)
+	services.Go(func() {
+		webhookDispatcher.Run(workerCtx, config.WebhookInterval)
+	})
+	trashService := services.NewTrashService(logger)
+	services.Go(func() {
+		trashService.RunPurger(workerCtx, config.TrashPurgeInterval, config.TrashRetention)
+	})
 
 	// 创建路由器
 	router := mux.NewRouter()
 
 	// 初始化路由
-	routes.InitializeRoutes(router)
+	routes.InitializeRoutes(router, logger)
+
+	// 应用请求日志、监控指标、CORS和语言协商中间件
+	handler := middleware.RequestLogger(logger)(
+		middleware.Metrics(router)(middleware.CorsMiddleware(middleware.LocaleMiddleware(router))),
+	)
 
-	// 应用CORS中间件
-	routerWithCors := middleware.CorsMiddleware(router)
+	server := &http.Server{
+		Addr:              config.ServerAddr,
+		Handler:           handler,
+		ReadHeaderTimeout: config.ServerReadHeaderTimeout,
+		ReadTimeout:       config.ServerReadTimeout,
+		WriteTimeout:      config.ServerWriteTimeout,
+		IdleTimeout:       config.ServerIdleTimeout,
+	}
+	// 关闭时结束实时推送的长连接，否则 Shutdown 会一直等待它们
+	server.RegisterOnShutdown(config.Realtime.Close)
 
 	// 启动服务器
-	log.Println("Starting server on :8080")
-	if err := http.ListenAndServe(":8080", routerWithCors); err != nil {
-		log.Fatal("Error starting server: ", err)
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("starting server", "addr", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		logger.Error("server stopped", "error", err)
+		os.Exit(1)
+	case <-ctx.Done():
+		stop()
+	}
+
+	// 先让就绪检查失败，等负载均衡停止转发新请求后再停止接收新连接，
+	// 并等待进行中的请求和后台任务完成。排空期间再次收到信号会直接退出
+	logger.Info("shutting down server", "drain_delay", config.ShutdownDrainDelay)
+	services.BeginShutdown()
+	time.Sleep(config.ShutdownDrainDelay)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("server shutdown failed", "error", err)
+	}
+	stopWorkers()
+	if err := services.WaitBackground(shutdownCtx); err != nil {
+		logger.Error("background tasks did not finish before timeout", "error", err)
+	}
+	if err := config.DB.Close(); err != nil {
+		logger.Error("close database failed", "error", err)
 	}
+	logger.Info("server stopped")
 }
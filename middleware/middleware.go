@@ -2,9 +2,14 @@ package middleware
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"my_blog/apperrors"
 	"my_blog/config"
+	"my_blog/i18n"
+	"my_blog/models"
+	"my_blog/utils"
 	"net/http"
 	"strings"
 	"time" // 添加 time 包导入
@@ -14,6 +19,26 @@ import (
 
 const secretKey = "your-secret-key" // 生产环境应使用更安全的方式存储
 
+// contextKey 请求上下文键类型，避免与其他包的键冲突
+type contextKey string
+
+const (
+	userIDKey contextKey = "userID"
+	roleIDKey contextKey = "roleID"
+)
+
+// UserIDFromContext 获取 AuthMiddleware 写入的当前用户ID
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(userIDKey).(int)
+	return userID, ok
+}
+
+// RoleFromContext 获取 AuthMiddleware 写入的当前用户角色ID，ID越小权限越高（见 utils.RoleAdmin）
+func RoleFromContext(ctx context.Context) (int, bool) {
+	roleID, ok := ctx.Value(roleIDKey).(int)
+	return roleID, ok
+}
+
 // Claims JWT声明结构
 type Claims struct {
 	UserID   int    `json:"user_id"`
@@ -25,8 +50,9 @@ type Claims struct {
 func CorsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "http://localhost:8081")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Origin,Accept")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Origin,Accept, Accept-Language, X-Request-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -37,45 +63,148 @@ func CorsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// LocaleMiddleware 协商响应语言：优先使用 lang 查询参数，其次 Accept-Language 头
+func LocaleMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale, ok := i18n.Parse(r.URL.Query().Get("lang"))
+		if !ok {
+			locale = i18n.Negotiate(r.Header.Get("Accept-Language"))
+		}
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r.WithContext(i18n.WithLocale(r.Context(), locale)))
+	})
+}
+
+// userState 认证时读取的用户角色、偏好语言和账号状态
+type userState struct {
+	roleID      int
+	locale      sql.NullString
+	status      string
+	statusUntil sql.NullTime
+}
+
+// loadUserState 读取用户的角色、偏好语言和账号状态
+func loadUserState(userID int) (userState, error) {
+	var state userState
+	err := config.DB.QueryRow(
+		"SELECT role_id, locale, status, status_until FROM users WHERE id = ?", userID,
+	).Scan(&state.roleID, &state.locale, &state.status, &state.statusUntil)
+	return state, err
+}
+
+// checkStatus 账号已注销时令牌失效，暂停或封禁期间拒绝访问
+func (state userState) checkStatus() error {
+	var until *time.Time
+	if state.statusUntil.Valid {
+		until = &state.statusUntil.Time
+	}
+	switch models.EffectiveStatus(state.status, until, time.Now()) {
+	case models.UserStatusDeleted:
+		return apperrors.Unauthorized(i18n.MsgInvalidToken)
+	case models.UserStatusSuspended:
+		return apperrors.Forbidden(i18n.MsgAccountSuspended)
+	case models.UserStatusBanned:
+		return apperrors.Forbidden(i18n.MsgAccountBanned)
+	}
+	return nil
+}
+
 // AuthMiddleware 验证用户身份
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := r.Header.Get("Authorization")
 		if token == "" {
-			http.Error(w, "未提供认证信息", http.StatusUnauthorized)
+			utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgMissingCredentials)
 			return
 		}
 
 		userID, err := validateToken(token)
 		if err != nil {
-			http.Error(w, "无效的认证信息", http.StatusUnauthorized)
+			utils.SendErrorResponse(w, r, http.StatusUnauthorized, i18n.MsgInvalidToken)
+			return
+		}
+
+		authed, err := withUser(r, userID)
+		if err != nil {
+			utils.SendError(w, r, err, i18n.MsgInternalError)
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), "userID", userID)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, authed)
 	})
 }
 
-// RoleMiddleware 验证用户角色权限
+// OptionalAuthMiddleware 可选的身份验证：未携带凭证、凭证无效（如已过期）或账号不可用时按匿名用户继续处理，
+// 用于公开接口中需要区分当前用户的场景。浏览器的 EventSource 无法设置请求头，因此也接受 access_token 查询参数
+func OptionalAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization")
+		if token == "" {
+			token = r.URL.Query().Get("access_token")
+		}
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userID, err := validateToken(token)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		authed, err := withUser(r, userID)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, authed)
+	})
+}
+
+// withUser 将已认证的用户及其角色写入请求上下文；用户不存在或账号不可用时返回领域错误
+func withUser(r *http.Request, userID int) (*http.Request, error) {
+	state, err := loadUserState(userID)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.Unauthorized(i18n.MsgInvalidToken)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := state.checkStatus(); err != nil {
+		return nil, err
+	}
+
+	r = setRequestUser(r, userID)
+	ctx := context.WithValue(r.Context(), userIDKey, userID)
+	ctx = context.WithValue(ctx, roleIDKey, state.roleID)
+	// 未显式指定语言时使用用户设置的偏好语言
+	if r.URL.Query().Get("lang") == "" {
+		if locale, ok := i18n.Parse(state.locale.String); ok {
+			ctx = i18n.WithLocale(ctx, locale)
+		}
+	}
+	return r.WithContext(ctx), nil
+}
+
+// RoleMiddleware 验证用户角色权限，角色ID越小权限越高，要求角色不低于 requiredRole
 func RoleMiddleware(requiredRole int) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			userID, ok := r.Context().Value("userID").(int)
-			if !ok {
-				http.Error(w, "无法获取用户信息", http.StatusInternalServerError)
+			if _, ok := UserIDFromContext(r.Context()); !ok {
+				utils.SendErrorResponse(w, r, http.StatusInternalServerError, i18n.MsgUserInfoUnavailable)
 				return
 			}
 
-			var roleID int
-			err := config.DB.QueryRow("SELECT role_id FROM users WHERE id = ?", userID).Scan(&roleID)
-			if err != nil {
-				http.Error(w, "无法获取用户角色", http.StatusInternalServerError)
+			roleID, ok := RoleFromContext(r.Context())
+			if !ok {
+				utils.SendErrorResponse(w, r, http.StatusInternalServerError, i18n.MsgUserRoleUnavailable)
 				return
 			}
 
-			if roleID < requiredRole {
-				http.Error(w, "权限不足", http.StatusForbidden)
+			if roleID > requiredRole {
+				utils.SendErrorResponse(w, r, http.StatusForbidden, i18n.MsgPermissionDenied)
 				return
 			}
 
@@ -98,7 +227,8 @@ func GenerateToken(userID int, username string, expiresIn time.Duration) (string
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secretKey))
 }
-/ VerifyToken 验证JWT Token
+
+// VerifyToken 验证JWT Token
 func VerifyToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
@@ -119,3 +249,13 @@ func VerifyToken(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
+
+// validateToken 解析Authorization头并返回用户ID
+func validateToken(header string) (int, error) {
+	tokenString := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	claims, err := VerifyToken(tokenString)
+	if err != nil {
+		return 0, err
+	}
+	return claims.UserID, nil
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"math"
+	"my_blog/config"
+	"my_blog/i18n"
+	"my_blog/logging"
+	"my_blog/ratelimit"
+	"my_blog/utils"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// RateLimitRule 单个路由的限流规则
+type RateLimitRule struct {
+	// Name 区分不同路由的限流键前缀
+	Name  string
+	Limit ratelimit.Limit
+	// PerUser 为true时已登录请求按用户ID限流（需放在 AuthMiddleware 之后），否则按客户端IP限流
+	PerUser bool
+}
+
+// RateLimit 按令牌桶限流，超出限制时返回429并通过 Retry-After 告知需等待的秒数。
+// 存储出错时放行请求，避免限流存储故障导致服务不可用
+func RateLimit(store ratelimit.Store, rule RateLimitRule) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := rule.Name + ":ip:" + clientIP(r)
+			if rule.PerUser {
+				if userID, ok := UserIDFromContext(r.Context()); ok {
+					key = rule.Name + ":user:" + strconv.Itoa(userID)
+				}
+			}
+
+			allowed, retryAfter, err := store.Take(key, rule.Limit)
+			if err != nil {
+				logging.FromContext(r.Context()).Error("rate limit store failed", "rule", rule.Name, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				if seconds < 1 {
+					seconds = 1
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				utils.SendErrorResponse(w, r, http.StatusTooManyRequests, i18n.MsgTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP 返回客户端IP。仅在 config.TrustProxyHeaders 开启（服务部署在反向代理之后）时
+// 使用 X-Forwarded-For 的最后一项，即代理实际看到的地址，否则该头可被客户端伪造
+func clientIP(r *http.Request) string {
+	if config.TrustProxyHeaders {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			parts := strings.Split(forwarded, ",")
+			if ip := strings.TrimSpace(parts[len(parts)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
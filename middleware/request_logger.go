@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"my_blog/logging"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// RequestIDHeader 请求ID的请求/响应头
+const RequestIDHeader = "X-Request-ID"
+
+const requestStateKey contextKey = "requestState"
+
+// requestIDPattern 客户端或网关传入的请求ID只接受安全字符，避免日志注入
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._\-]{1,64}$`)
+
+// requestState 请求处理过程中由内层中间件补充的信息，供请求日志使用
+type requestState struct {
+	userID int
+}
+
+// statusRecorder 记录响应状态码和写出的字节数
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// Flush 支持流式响应
+func (rec *statusRecorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap 供 http.ResponseController 访问底层连接
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// RequestLogger 为每个请求分配请求ID并记录访问日志。
+// 请求ID优先沿用请求头中的 X-Request-ID，并写入响应头；
+// 请求上下文中的日志记录器带有 request_id 字段，处理过程中的错误日志可据此关联到请求
+func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(RequestIDHeader)
+			if !requestIDPattern.MatchString(requestID) {
+				requestID = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			state := &requestState{}
+			ctx := logging.WithRequestID(r.Context(), requestID)
+			ctx = logging.NewContext(ctx, logger.With("request_id", requestID))
+			ctx = context.WithValue(ctx, requestStateKey, state)
+
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			attrs := []any{
+				"request_id", requestID,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", rec.status,
+				"bytes", rec.bytes,
+				"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+				"remote_addr", r.RemoteAddr,
+			}
+			if state.userID != 0 {
+				attrs = append(attrs, "user_id", state.userID)
+			}
+
+			level := slog.LevelInfo
+			switch {
+			case rec.status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case rec.status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+			logger.Log(r.Context(), level, "request", attrs...)
+		})
+	}
+}
+
+// newRequestID 生成随机请求ID
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// setRequestUser 记录当前请求的用户，并为请求日志记录器附加 user_id 字段
+func setRequestUser(r *http.Request, userID int) *http.Request {
+	ctx := r.Context()
+	if state, ok := ctx.Value(requestStateKey).(*requestState); ok {
+		state.userID = userID
+	}
+	ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("user_id", userID))
+	return r.WithContext(ctx)
+}
@@ -1,16 +1,22 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
 // Comment 评论模型
 type Comment struct {
-	ID        int       `json:"id"`
-	ArticleID int       `json:"article_id"`
-	Content   string    `json:"content"`
-	Author    string    `json:"author"`
-	CreateAt  time.Time `json:"create_at"`
+	ID        int        `json:"id"`
+	ArticleID int        `json:"article_id"`
+	Content   string     `json:"content"`
+	Author    string     `json:"author"`
+	CreateAt  time.Time  `json:"create_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"` // 最后编辑时间，未编辑过为空
+	UserID    int        `json:"user_id,omitempty"`    // 发表评论的用户，旧评论为0
+	ParentID  *int       `json:"parent_id,omitempty"`  // 回复的评论
+
+	*ReactionSummary
 }
 
 // Article 文章模型
@@ -19,10 +25,76 @@ type Article struct {
 	Title     string    `json:"title"`
 	Content   string    `json:"content"`
 	Author    string    `json:"author"`
+	AuthorID  int       `json:"author_id,omitempty"` // 发布文章的用户，作者没有账号的旧文章为0
 	CreateAt  time.Time `json:"create_at"`
 	ImagePath *string   `json:"image_path,omitempty"`
 	Category  Category  `json:"category"`
 	Views     int       `json:"views"`
+
+	ImageVariants *ImageVariants `json:"image_variants,omitempty"`
+
+	*ReactionSummary
+}
+
+// ArticleList 分页的文章列表，Total 为总数
+type ArticleList struct {
+	Items []Article `json:"items"`
+	Total int       `json:"total"`
+}
+
+// ArticleFeed 游标分页的文章列表，NextCursor 为空表示没有更多
+type ArticleFeed struct {
+	Items      []Article `json:"items"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// Trashed 回收站中内容的删除信息
+type Trashed struct {
+	DeletedAt time.Time `json:"deleted_at"`
+	DeletedBy *int      `json:"deleted_by,omitempty"` // 执行删除的用户
+	PurgeAt   time.Time `json:"purge_at"`             // 超过保留期后永久删除的时间
+}
+
+// TrashedArticle 回收站中的文章
+type TrashedArticle struct {
+	Article
+	Trashed
+}
+
+// TrashedArticleList 分页的回收站文章列表，Total 为总数
+type TrashedArticleList struct {
+	Items []TrashedArticle `json:"items"`
+	Total int              `json:"total"`
+}
+
+// TrashedComment 回收站中的评论
+type TrashedComment struct {
+	Comment
+	Trashed
+}
+
+// TrashedCommentList 分页的回收站评论列表，Total 为总数
+type TrashedCommentList struct {
+	Items []TrashedComment `json:"items"`
+	Total int              `json:"total"`
+}
+
+// ReadingList 用户的阅读列表，Articles 只在获取单个列表时按顺序返回
+type ReadingList struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	ArticleCount int       `json:"article_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Articles     []Article `json:"articles,omitempty"`
+}
+
+// ReactionSummary 文章或评论的反应统计，嵌入后字段直接出现在文章/评论的JSON中，未加载时不输出
+type ReactionSummary struct {
+	Reactions   map[string]int `json:"reactions"`    // 各反应的数量，没有反应的类型不出现
+	MyReactions []string       `json:"my_reactions"` // 当前用户的反应，未登录时为空
+	LikedByMe   bool           `json:"liked_by_me"`
 }
 
 // UserArticleCount 用户文章统计
@@ -40,19 +112,226 @@ type Category struct {
 
 // User 用户模型
 type User struct {
+	ID              int        `json:"id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	Password        string     `json:"-"` // 密码字段，不输出到JSON
+	ImageData       string     `json:"image_data"`
+	RoleID          int        `json:"role_id"`
+	Status          string     `json:"status"` // 账号状态，见 UserStatusActive 等
+	BackgroundImage string     `json:"background_image"`
+	Locale          string     `json:"locale,omitempty"` // 偏好语言，为空时按 Accept-Language 协商
+	EmailVerified   bool       `json:"email_verified"`
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusUntil     *time.Time `json:"status_until,omitempty"`
+	FollowerCount   int        `json:"follower_count"`  // 关注该用户的人数
+	FollowingCount  int        `json:"following_count"` // 该用户关注的人数
+
+	Profile
+
+	ImageVariants      *ImageVariants `json:"image_variants,omitempty"`
+	BackgroundVariants *ImageVariants `json:"background_variants,omitempty"`
+}
+
+// 账号状态
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended" // 暂停使用，可设置截止时间，到期后自动恢复
+	UserStatusBanned    = "banned"
+	UserStatusDeleted   = "deleted" // 已注销，资料已匿名化
+)
+
+// EffectiveStatus 考虑暂停截止时间后的账号状态，暂停已到期时视为正常
+func EffectiveStatus(status string, until *time.Time, now time.Time) string {
+	if status == UserStatusSuspended && until != nil && !until.After(now) {
+		return UserStatusActive
+	}
+	return status
+}
+
+// UserStatusChange 管理员修改账号状态，Until 只用于暂停
+type UserStatusChange struct {
+	Status string
+	Reason string
+	Until  *time.Time
+}
+
+// Profile 用户在个人主页上展示的资料
+type Profile struct {
+	Bio         string      `json:"bio"`
+	Website     string      `json:"website"`
+	Location    string      `json:"location"`
+	SocialLinks SocialLinks `json:"social_links"`
+}
+
+// SocialLinks 社交账号链接，未填写的不输出
+type SocialLinks struct {
+	GitHub   string `json:"github,omitempty"`
+	Twitter  string `json:"twitter,omitempty"`
+	Weibo    string `json:"weibo,omitempty"`
+	Zhihu    string `json:"zhihu,omitempty"`
+	LinkedIn string `json:"linkedin,omitempty"`
+}
+
+// AuthorProfile 作者的公开主页，只包含可公开的资料，不含邮箱、密码、角色等，由 serializers.Author 转换为响应
+type AuthorProfile struct {
 	ID              int    `json:"id"`
 	Username        string `json:"username"`
-	Email           string `json:"email"`
-	Password        string `json:"password"` // 密码字段，不输出到JSON
 	ImageData       string `json:"image_data"`
-	RoleID          int    `json:"role_id"`
-	Status          int    `json:"status"`
 	BackgroundImage string `json:"background_image"`
+	Profile
+	Stats        AuthorStats `json:"stats"`
+	FollowedByMe bool        `json:"followed_by_me"` // 当前用户是否已关注，未登录时为false
+	Articles     []Article   `json:"articles"`       // 最近发布的文章
+
+	ImageVariants      *ImageVariants `json:"image_variants,omitempty"`
+	BackgroundVariants *ImageVariants `json:"background_variants,omitempty"`
+}
+
+// AuthorStats 作者的统计数据
+type AuthorStats struct {
+	Articles          int `json:"articles"`           // 发布的文章数
+	Views             int `json:"views"`              // 文章总阅读量
+	Comments          int `json:"comments"`           // 发表的评论数
+	ReactionsReceived int `json:"reactions_received"` // 文章收到的反应数
+	Followers         int `json:"followers"`
+	Following         int `json:"following"`
+}
+
+// Notification 站内通知，Data 为通知类型相关的展示数据（如文章标题、评论摘要）
+type Notification struct {
+	ID        int64                  `json:"id"`
+	Type      string                 `json:"type"`
+	ArticleID *int                   `json:"article_id,omitempty"`
+	CommentID *int                   `json:"comment_id,omitempty"`
+	Data      map[string]interface{} `json:"data"`
+	Read      bool                   `json:"read"`
+	CreateAt  time.Time              `json:"create_at"`
+}
+
+// NotificationList 通知列表及未读数量
+type NotificationList struct {
+	Items       []Notification `json:"items"`
+	UnreadCount int            `json:"unread_count"`
+}
+
+// NotificationPreferences 用户的邮件通知偏好
+type NotificationPreferences struct {
+	EmailComment    bool `json:"email_comment"`    // 文章有新评论
+	EmailReply      bool `json:"email_reply"`      // 评论被回复
+	EmailModeration bool `json:"email_moderation"` // 评论被移除
+	EmailAccount    bool `json:"email_account"`    // 密码修改、账号锁定等账号安全事件
+}
+
+// Webhook 外部系统的事件订阅，Secret 只在创建时返回
+type Webhook struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDelivery Webhook 的一次事件投递及其结果
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int             `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"` // pending、success 或 failed
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int            `json:"response_status"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+}
+
+// ImageVariants 上传图片的多尺寸变体访问路径
+type ImageVariants struct {
+	Thumbnail string `json:"thumbnail"`
+	Medium    string `json:"medium"`
+	Large     string `json:"large"`
 }
 
-// APIResponse API响应格式
+// APIResponse API响应格式，Message 按请求语言本地化，ErrorCode 为稳定的机器可读错误码
 type APIResponse struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data"`
+	Code      int          `json:"code"`
+	Message   string       `json:"message"`
+	ErrorCode string       `json:"error_code,omitempty"`
+	Data      interface{}  `json:"data"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError 请求字段校验错误，Reason 由 Code 和 Params 本地化生成
+type FieldError struct {
+	Field  string        `json:"field"`
+	Code   string        `json:"code"`
+	Reason string        `json:"reason"`
+	Params []interface{} `json:"-"`
+}
+
+// Optional 部分更新（JSON Merge Patch）中的字段：Set 表示请求中出现了该字段，Null 表示显式设为 null
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// Some 返回已设置为 value 的字段
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{Set: true, Value: value}
+}
+
+// UnmarshalJSON 只有出现在请求中的字段才会被调用，包括值为 null 的字段
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// Present 字段是否出现在请求中
+func (o Optional[T]) Present() bool {
+	return o.Set
+}
+
+// Current 返回字段的值，显式为 null 时返回nil
+func (o Optional[T]) Current() interface{} {
+	if o.Null {
+		return nil
+	}
+	return o.Value
+}
+
+// UserPatch 用户信息的部分更新，只修改出现的字段；Locale、Bio、Website、Location 为 null 时清空
+type UserPatch struct {
+	Username    Optional[string]
+	Email       Optional[string]
+	Password    Optional[string]
+	ImageData   Optional[string]
+	Locale      Optional[string]
+	Bio         Optional[string]
+	Website     Optional[string]
+	Location    Optional[string]
+	SocialLinks Optional[SocialLinksPatch] // 为 null 时移除全部社交链接
+}
+
+// SocialLinksPatch 社交链接的部分更新，为 null 的链接被移除
+type SocialLinksPatch struct {
+	GitHub   Optional[string]
+	Twitter  Optional[string]
+	Weibo    Optional[string]
+	Zhihu    Optional[string]
+	LinkedIn Optional[string]
+}
+
+// ArticlePatch 文章的部分更新，只修改出现的字段；ImagePath 为 null 时移除配图
+type ArticlePatch struct {
+	Title     Optional[string]
+	Content   Optional[string]
+	ImagePath Optional[string]
 }
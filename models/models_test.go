@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEffectiveStatus(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		v := now.Add(d)
+		return &v
+	}
+	tests := []struct {
+		name   string
+		status string
+		until  *time.Time
+		want   string
+	}{
+		{"active", UserStatusActive, nil, UserStatusActive},
+		{"suspended indefinitely", UserStatusSuspended, nil, UserStatusSuspended},
+		{"suspension running", UserStatusSuspended, at(time.Second), UserStatusSuspended},
+		{"suspension ends now", UserStatusSuspended, at(0), UserStatusActive},
+		{"suspension expired", UserStatusSuspended, at(-time.Hour), UserStatusActive},
+		{"banned ignores until", UserStatusBanned, at(-time.Hour), UserStatusBanned},
+		{"deleted ignores until", UserStatusDeleted, at(-time.Hour), UserStatusDeleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EffectiveStatus(tt.status, tt.until, now); got != tt.want {
+				t.Errorf("EffectiveStatus(%q, %v) = %q, want %q", tt.status, tt.until, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit 令牌桶参数：Rate 为每秒补充的令牌数（须大于0），Burst 为桶容量（允许的突发请求数）
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute 每分钟补充 n 个令牌，最多累积 burst 个
+func PerMinute(n, burst int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: burst}
+}
+
+// PerHour 每小时补充 n 个令牌，最多累积 burst 个
+func PerHour(n, burst int) Limit {
+	return Limit{Rate: float64(n) / 3600, Burst: burst}
+}
+
+// Store 令牌桶存储。默认的 MemoryStore 只在单个实例内生效，
+// 多实例部署时可实现基于 Redis 等共享存储的版本，Take 需保证原子性
+type Store interface {
+	// Take 从 key 对应的桶中取一个令牌；令牌不足时返回false及需要等待的时间
+	Take(key string, limit Limit) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// bucket 单个限流键的令牌桶
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// refill 按经过的时间补充令牌
+func (b *bucket) refill(now time.Time) float64 {
+	return math.Min(float64(b.limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*b.limit.Rate)
+}
+
+// MemoryStore 进程内令牌桶存储
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// sweepInterval 清理已回满的空闲桶的周期，避免长期运行时内存持续增长
+const sweepInterval = time.Minute
+
+// NewMemoryStore 创建进程内令牌桶存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Take 实现 Store
+func (s *MemoryStore) Take(key string, limit Limit) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now, limit: limit}
+		s.buckets[key] = b
+	} else {
+		b.limit = limit
+		b.tokens = b.refill(now)
+		b.updated = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+	wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	return false, wait, nil
+}
+
+// sweep 定期删除已补满的桶，这些桶与新建的桶状态相同
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if b.refill(now) >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
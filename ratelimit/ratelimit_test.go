@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock 可手动推进的时钟
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = clock.now
+	return s, clock
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	type step struct {
+		advance    time.Duration
+		allowed    bool
+		retryAfter time.Duration
+	}
+	tests := []struct {
+		name  string
+		limit Limit
+		steps []step
+	}{
+		{
+			name:  "burst then wait",
+			limit: Limit{Rate: 1, Burst: 2},
+			steps: []step{
+				{0, true, 0},
+				{0, true, 0},
+				{0, false, time.Second},
+			},
+		},
+		{
+			name:  "partial refill shortens wait",
+			limit: Limit{Rate: 1, Burst: 1},
+			steps: []step{
+				{0, true, 0},
+				{250 * time.Millisecond, false, 750 * time.Millisecond},
+				{750 * time.Millisecond, true, 0},
+			},
+		},
+		{
+			name:  "refill capped at burst",
+			limit: Limit{Rate: 1, Burst: 2},
+			steps: []step{
+				{0, true, 0},
+				{0, true, 0},
+				{time.Hour, true, 0},
+				{0, true, 0},
+				{0, false, time.Second},
+			},
+		},
+		{
+			name:  "per minute",
+			limit: PerMinute(6, 1),
+			steps: []step{
+				{0, true, 0},
+				{time.Second, false, 9 * time.Second},
+				{9 * time.Second, true, 0},
+			},
+		},
+		{
+			name:  "zero burst never allows",
+			limit: Limit{Rate: 1, Burst: 0},
+			steps: []step{
+				{0, false, time.Second},
+				{time.Hour, false, time.Second},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, clock := newTestStore()
+			for i, st := range tt.steps {
+				clock.t = clock.t.Add(st.advance)
+				allowed, retryAfter, err := s.Take("key", tt.limit)
+				if err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+				if allowed != st.allowed || retryAfter != st.retryAfter {
+					t.Errorf("step %d: Take = %v, %v, want %v, %v", i, allowed, retryAfter, st.allowed, st.retryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreKeysIndependent(t *testing.T) {
+	s, _ := newTestStore()
+	limit := Limit{Rate: 1, Burst: 1}
+	if ok, _, _ := s.Take("a", limit); !ok {
+		t.Fatal("first take on a rejected")
+	}
+	if ok, _, _ := s.Take("b", limit); !ok {
+		t.Error("take on b affected by a")
+	}
+	if ok, _, _ := s.Take("a", limit); ok {
+		t.Error("second take on a allowed")
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s, clock := newTestStore()
+	s.Take("idle", Limit{Rate: 1, Burst: 1})
+	s.Take("slow", Limit{Rate: 0.001, Burst: 1})
+
+	clock.t = clock.t.Add(sweepInterval)
+	s.Take("other", Limit{Rate: 1, Burst: 1})
+
+	if _, ok := s.buckets["idle"]; ok {
+		t.Error("refilled bucket not swept")
+	}
+	if _, ok := s.buckets["slow"]; !ok {
+		t.Error("bucket still refilling was swept")
+	}
+}
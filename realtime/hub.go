@@ -0,0 +1,138 @@
+package realtime
+
+import (
+	"strconv"
+	"sync"
+)
+
+// ArticlesTopic 新文章发布公告
+const ArticlesTopic = "articles"
+
+// CommentsTopic 某篇文章的评论流
+func CommentsTopic(articleID int) string {
+	return "article:" + strconv.Itoa(articleID) + ":comments"
+}
+
+// UserTopic 某个用户的站内通知
+func UserTopic(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
+
+// Event 推送给订阅者的事件，Data 会被编码为JSON
+type Event struct {
+	Type string
+	Data interface{}
+}
+
+// subscriberBuffer 每个订阅者的事件缓冲区大小
+const subscriberBuffer = 32
+
+// Hub 进程内发布/订阅中心。只在单个实例内生效，
+// 多实例部署时需要通过 Redis 等消息中间件在实例间转发事件
+type Hub struct {
+	mu     sync.Mutex
+	topics map[string]map[*Subscription]struct{}
+	closed bool
+}
+
+// NewHub 创建发布/订阅中心
+func NewHub() *Hub {
+	return &Hub{topics: make(map[string]map[*Subscription]struct{})}
+}
+
+// Subscription 一个订阅者，从 Events 接收事件；Done 关闭表示订阅已结束
+// （订阅者主动取消、消费过慢被断开或 Hub 关闭），此后不会再收到事件
+type Subscription struct {
+	hub    *Hub
+	topics []string
+	events chan Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Subscribe 订阅一个或多个主题，使用完毕后须调用 Close
+func (h *Hub) Subscribe(topics ...string) *Subscription {
+	sub := &Subscription{
+		hub:    h,
+		topics: topics,
+		events: make(chan Event, subscriberBuffer),
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		sub.stop()
+		return sub
+	}
+	for _, topic := range topics {
+		subs := h.topics[topic]
+		if subs == nil {
+			subs = make(map[*Subscription]struct{})
+			h.topics[topic] = subs
+		}
+		subs[sub] = struct{}{}
+	}
+	return sub
+}
+
+// Publish 向主题的所有订阅者发送事件，不会阻塞；
+// 缓冲区已满的订阅者会被断开，由客户端重新连接并重新拉取数据
+func (h *Hub) Publish(topic string, event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.topics[topic] {
+		select {
+		case sub.events <- event:
+		default:
+			h.remove(sub)
+			sub.stop()
+		}
+	}
+}
+
+// Close 结束所有订阅，之后的订阅会立即结束。服务关闭时调用，使长连接及时返回
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, subs := range h.topics {
+		for sub := range subs {
+			sub.stop()
+		}
+	}
+	h.topics = make(map[string]map[*Subscription]struct{})
+}
+
+// remove 从所有主题中移除订阅者，调用方须持有锁
+func (h *Hub) remove(sub *Subscription) {
+	for _, topic := range sub.topics {
+		subs := h.topics[topic]
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+}
+
+// Events 接收事件的通道
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Done 订阅结束时关闭
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close 取消订阅
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	s.hub.remove(s)
+	s.hub.mu.Unlock()
+	s.stop()
+}
+
+func (s *Subscription) stop() {
+	s.once.Do(func() { close(s.done) })
+}
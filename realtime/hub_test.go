@@ -0,0 +1,107 @@
+package realtime
+
+import "testing"
+
+// isDone 判断订阅是否已结束
+func isDone(sub *Subscription) bool {
+	select {
+	case <-sub.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestPublishDeliversToTopicSubscribers(t *testing.T) {
+	hub := NewHub()
+	a := hub.Subscribe(ArticlesTopic, UserTopic(1))
+	b := hub.Subscribe(CommentsTopic(7))
+	defer a.Close()
+	defer b.Close()
+
+	hub.Publish(UserTopic(1), Event{Type: "notification"})
+	hub.Publish(CommentsTopic(8), Event{Type: "comment"})
+
+	if got := len(a.Events()); got != 1 {
+		t.Fatalf("a received %d events, want 1", got)
+	}
+	if ev := <-a.Events(); ev.Type != "notification" {
+		t.Errorf("a received %q", ev.Type)
+	}
+	if got := len(b.Events()); got != 0 {
+		t.Errorf("b received %d events from another topic", got)
+	}
+}
+
+func TestPublishDropsSlowSubscriber(t *testing.T) {
+	hub := NewHub()
+	slow := hub.Subscribe(ArticlesTopic, UserTopic(1))
+	fast := hub.Subscribe(ArticlesTopic)
+	defer fast.Close()
+
+	for i := 0; i < subscriberBuffer; i++ {
+		hub.Publish(ArticlesTopic, Event{Type: "article"})
+		<-fast.Events()
+	}
+	if isDone(slow) {
+		t.Fatal("subscriber dropped before its buffer was full")
+	}
+
+	// 缓冲区已满，下一个事件使慢订阅者被断开，且不阻塞其他订阅者
+	hub.Publish(ArticlesTopic, Event{Type: "article"})
+	if !isDone(slow) {
+		t.Fatal("slow subscriber not dropped")
+	}
+	if got := len(fast.Events()); got != 1 {
+		t.Errorf("fast subscriber received %d events, want 1", got)
+	}
+
+	// 慢订阅者从它订阅的所有主题中移除
+	hub.mu.Lock()
+	_, inArticles := hub.topics[ArticlesTopic][slow]
+	_, userTopic := hub.topics[UserTopic(1)]
+	hub.mu.Unlock()
+	if inArticles || userTopic {
+		t.Error("dropped subscriber still registered")
+	}
+	if got := len(slow.Events()); got != subscriberBuffer {
+		t.Errorf("buffered events = %d, want %d", got, subscriberBuffer)
+	}
+
+	// 重复关闭不会 panic
+	slow.Close()
+}
+
+func TestCloseEndsSubscriptions(t *testing.T) {
+	hub := NewHub()
+	sub := hub.Subscribe(ArticlesTopic)
+	hub.Close()
+	if !isDone(sub) {
+		t.Error("subscription not ended by Close")
+	}
+
+	late := hub.Subscribe(ArticlesTopic)
+	if !isDone(late) {
+		t.Error("subscription after Close not ended")
+	}
+	hub.Publish(ArticlesTopic, Event{Type: "article"})
+	if got := len(late.Events()); got != 0 {
+		t.Errorf("closed hub delivered %d events", got)
+	}
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	hub := NewHub()
+	sub := hub.Subscribe(ArticlesTopic)
+	sub.Close()
+	if !isDone(sub) {
+		t.Error("Close did not end subscription")
+	}
+	hub.Publish(ArticlesTopic, Event{Type: "article"})
+	if got := len(sub.Events()); got != 0 {
+		t.Errorf("closed subscription received %d events", got)
+	}
+	if len(hub.topics) != 0 {
+		t.Errorf("topics not cleaned up: %v", hub.topics)
+	}
+}
@@ -1,33 +1,69 @@
 package routes
 
 import (
+	"log/slog"
+	"my_blog/config"
 	"my_blog/controllers"
+	"my_blog/metrics"
 	"my_blog/middleware"
 	"my_blog/utils"
+	"net/http"
 
 	"github.com/gorilla/mux"
-
-	"github.com/gin-gonic/gin"
 )
 
 // InitializeRoutes 初始化路由
-func InitializeRoutes(router *mux.Router) {
+func InitializeRoutes(router *mux.Router, logger *slog.Logger) {
 	// 创建控制器实例
-	articleController := controllers.NewArticleController()
-	userController := controllers.NewUserController()
-	commentController := controllers.NewCommentController()
+	articleController := controllers.NewArticleController(logger)
+	userController := controllers.NewUserController(logger)
+	commentController := controllers.NewCommentController(logger)
 	categoryController := controllers.NewCategoryController()
-	r := gin.Default()
-	// 映射 URL 路径 `/uploads/` 到本地目录 `./uploads`（与你的目录结构一致）
-	r.Static("/uploads", "./uploads")
+	uploadController := controllers.NewUploadController()
+	healthController := controllers.NewHealthController()
+	accountController := controllers.NewAccountController(logger)
+	notificationController := controllers.NewNotificationController(logger)
+	streamController := controllers.NewStreamController()
+	webhookController := controllers.NewWebhookController(logger)
+	reactionController := controllers.NewReactionController()
+	bookmarkController := controllers.NewBookmarkController()
+	readingListController := controllers.NewReadingListController()
+	followController := controllers.NewFollowController()
+	authorController := controllers.NewAuthorController()
+	trashController := controllers.NewTrashController(logger)
+
+	// 限流：登录和注册按IP，防止暴力破解和批量注册；发表评论按用户，防止刷屏
+	loginLimit := middleware.RateLimit(config.RateLimitStore, middleware.RateLimitRule{Name: "login", Limit: config.LoginRateLimit})
+	registerLimit := middleware.RateLimit(config.RateLimitStore, middleware.RateLimitRule{Name: "register", Limit: config.RegisterRateLimit})
+	mailLimit := middleware.RateLimit(config.RateLimitStore, middleware.RateLimitRule{Name: "mail", Limit: config.MailRateLimit, PerUser: true})
+	commentLimit := middleware.RateLimit(config.RateLimitStore, middleware.RateLimitRule{Name: "comment", Limit: config.CommentRateLimit, PerUser: true})
+
+	// 上传文件访问，映射 `/uploads/` 到存储后端
+	router.PathPrefix("/uploads/").HandlerFunc(uploadController.ServeUpload).Methods("GET", "HEAD")
+
+	// 监控指标和健康检查
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+	router.HandleFunc("/healthz", healthController.Healthz).Methods("GET")
+	router.HandleFunc("/readyz", healthController.Readyz).Methods("GET")
+
 	// 公共API，无需认证
-	router.HandleFunc("/register", userController.Register).Methods("POST")
-	router.HandleFunc("/login", userController.Login).Methods("POST")
-	router.HandleFunc("/articles", articleController.GetArticles).Methods("GET")
-	router.HandleFunc("/articles/{id}", articleController.GetArticle).Methods("GET")
+	router.Handle("/register", registerLimit(http.HandlerFunc(userController.Register))).Methods("POST")
+	router.Handle("/login", loginLimit(http.HandlerFunc(userController.Login))).Methods("POST")
+	router.HandleFunc("/email/verify", accountController.VerifyEmail).Methods("POST")
+	router.Handle("/password/forgot", mailLimit(http.HandlerFunc(accountController.ForgotPassword))).Methods("POST")
+	router.HandleFunc("/password/reset", accountController.ResetPassword).Methods("POST")
+	// 文章列表和详情公开访问，登录用户额外返回自己的反应
+	router.Handle("/articles", middleware.OptionalAuthMiddleware(http.HandlerFunc(articleController.GetArticles))).Methods("GET")
+	router.Handle("/articles/{id}", middleware.OptionalAuthMiddleware(http.HandlerFunc(articleController.GetArticle))).Methods("GET")
 	router.HandleFunc("/categories", categoryController.GetCategories).Methods("GET")
 	router.HandleFunc("/categories/{id}", categoryController.GetCategory).Methods("GET")
-	router.HandleFunc("/categories/{id}/articles", articleController.GetArticlesByCategory).Methods("GET")
+	router.Handle("/categories/{id}/articles", middleware.OptionalAuthMiddleware(http.HandlerFunc(articleController.GetArticlesByCategory))).Methods("GET")
+	// 作者公开主页，登录用户额外返回是否已关注
+	router.Handle("/authors/{username}", middleware.OptionalAuthMiddleware(http.HandlerFunc(authorController.GetAuthor))).Methods("GET")
+	router.Handle("/authors/{username}/articles", middleware.OptionalAuthMiddleware(http.HandlerFunc(authorController.GetAuthorArticles))).Methods("GET")
+
+	// 实时推送，登录用户额外接收自己的站内通知
+	router.Handle("/events", middleware.OptionalAuthMiddleware(http.HandlerFunc(streamController.Events))).Methods("GET")
 
 	// 需要认证的API
 	authRouter := router.PathPrefix("").Subrouter()
@@ -35,19 +71,56 @@ func InitializeRoutes(router *mux.Router) {
 
 	// 用户相关API
 	authRouter.HandleFunc("/users/me", userController.GetCurrentUser).Methods("GET")
+	authRouter.HandleFunc("/users/me", userController.UpdateCurrentUser).Methods("PUT", "PATCH")
+	authRouter.HandleFunc("/users/me/password", accountController.ChangePassword).Methods("PUT")
+	authRouter.HandleFunc("/users/me/profile", userController.UpdateProfile).Methods("PUT")
+	authRouter.HandleFunc("/users/me/notification-preferences", notificationController.GetPreferences).Methods("GET")
+	authRouter.HandleFunc("/users/me/notification-preferences", notificationController.UpdatePreferences).Methods("PUT")
+	authRouter.HandleFunc("/notifications", notificationController.ListNotifications).Methods("GET")
+	authRouter.HandleFunc("/notifications/read-all", notificationController.MarkAllRead).Methods("PUT")
+	authRouter.HandleFunc("/notifications/{id}/read", notificationController.MarkRead).Methods("PUT")
+	authRouter.Handle("/users/me/email-verification", mailLimit(http.HandlerFunc(accountController.ResendVerification))).Methods("POST")
+	authRouter.HandleFunc("/users/me/bookmarks", bookmarkController.GetBookmarks).Methods("GET")
+	authRouter.HandleFunc("/users/me/reading-lists", readingListController.GetReadingLists).Methods("GET")
+	authRouter.HandleFunc("/users/me/reading-lists", readingListController.CreateReadingList).Methods("POST")
+	authRouter.HandleFunc("/users/me/reading-lists/{id}", readingListController.GetReadingList).Methods("GET")
+	authRouter.HandleFunc("/users/me/reading-lists/{id}", readingListController.UpdateReadingList).Methods("PUT")
+	authRouter.HandleFunc("/users/me/reading-lists/{id}", readingListController.DeleteReadingList).Methods("DELETE")
+	authRouter.HandleFunc("/users/me/reading-lists/{id}/order", readingListController.ReorderArticles).Methods("PUT")
+	authRouter.HandleFunc("/users/me/reading-lists/{id}/articles/{articleId}", readingListController.AddArticle).Methods("PUT")
+	authRouter.HandleFunc("/users/me/reading-lists/{id}/articles/{articleId}", readingListController.RemoveArticle).Methods("DELETE")
 	authRouter.HandleFunc("/users/{id}/background-image", userController.UpdateUserBackgroundImage).Methods("POST")
-	authRouter.HandleFunc("/users/{id}", userController.UpdateUser).Methods("PUT")
+	authRouter.HandleFunc("/users/{id}", userController.UpdateUser).Methods("PUT", "PATCH")
+	authRouter.HandleFunc("/users/{id}/follow", followController.FollowUser).Methods("PUT")
+	authRouter.HandleFunc("/users/{id}/follow", followController.UnfollowUser).Methods("DELETE")
+	authRouter.HandleFunc("/categories/{id}/follow", followController.FollowCategory).Methods("PUT")
+	authRouter.HandleFunc("/categories/{id}/follow", followController.UnfollowCategory).Methods("DELETE")
+	authRouter.HandleFunc("/feed", followController.GetFeed).Methods("GET")
 
 	// 文章相关API
 	authRouter.HandleFunc("/articles", articleController.CreateArticle).Methods("POST")
 	authRouter.HandleFunc("/articles/{id}", articleController.UpdateArticle).Methods("PUT")
+	authRouter.HandleFunc("/articles/{id}", articleController.PatchArticle).Methods("PATCH")
 	authRouter.HandleFunc("/articles/{id}", articleController.DeleteArticle).Methods("DELETE")
+	authRouter.HandleFunc("/articles/{id}/reactions", reactionController.ToggleArticleReaction).Methods("POST")
+	authRouter.HandleFunc("/articles/{id}/bookmark", bookmarkController.AddBookmark).Methods("PUT")
+	authRouter.HandleFunc("/articles/{id}/bookmark", bookmarkController.RemoveBookmark).Methods("DELETE")
+	authRouter.HandleFunc("/articles/{id}/restore", trashController.RestoreArticle).Methods("POST")
+
+	// 上传相关API
+	authRouter.HandleFunc("/uploads", uploadController.UploadImage).Methods("POST")
 
 	// 评论相关API
 	authRouter.HandleFunc("/articles/{id}/comments", commentController.GetCommentsByArticle).Methods("GET")
-	authRouter.HandleFunc("/articles/{id}/comments", commentController.CreateComment).Methods("POST")
+	authRouter.Handle("/articles/{id}/comments", commentLimit(http.HandlerFunc(commentController.CreateComment))).Methods("POST")
 	authRouter.HandleFunc("/comments/{id}", commentController.UpdateComment).Methods("PUT")
 	authRouter.HandleFunc("/comments/{id}", commentController.DeleteComment).Methods("DELETE")
+	authRouter.HandleFunc("/comments/{id}/reactions", reactionController.ToggleCommentReaction).Methods("POST")
+	authRouter.HandleFunc("/comments/{id}/restore", trashController.RestoreComment).Methods("POST")
+
+	// 回收站API，作者查看自己删除的内容，管理员查看所有内容
+	authRouter.HandleFunc("/trash/articles", trashController.GetTrashedArticles).Methods("GET")
+	authRouter.HandleFunc("/trash/comments", trashController.GetTrashedComments).Methods("GET")
 
 	// 需要管理员权限的API
 	adminRouter := authRouter.PathPrefix("").Subrouter()
@@ -57,9 +130,18 @@ func InitializeRoutes(router *mux.Router) {
 	adminRouter.HandleFunc("/users", userController.GetAllUsers).Methods("GET")
 	adminRouter.HandleFunc("/users/{id}", userController.DeleteUser).Methods("DELETE")
 	adminRouter.HandleFunc("/users/{id}/role", userController.UpdateUserRole).Methods("PUT")
+	adminRouter.HandleFunc("/users/{id}/status", userController.UpdateUserStatus).Methods("PUT")
 
 	// 分类管理API
 	adminRouter.HandleFunc("/categories", categoryController.CreateCategory).Methods("POST")
 	adminRouter.HandleFunc("/categories/{id}", categoryController.UpdateCategory).Methods("PUT")
 	adminRouter.HandleFunc("/categories/{id}", categoryController.DeleteCategory).Methods("DELETE")
+
+	// Webhook 管理API
+	adminRouter.HandleFunc("/webhooks", webhookController.GetWebhooks).Methods("GET")
+	adminRouter.HandleFunc("/webhooks", webhookController.CreateWebhook).Methods("POST")
+	adminRouter.HandleFunc("/webhooks/{id}", webhookController.UpdateWebhook).Methods("PUT")
+	adminRouter.HandleFunc("/webhooks/{id}", webhookController.DeleteWebhook).Methods("DELETE")
+	adminRouter.HandleFunc("/webhooks/{id}/deliveries", webhookController.GetDeliveries).Methods("GET")
+	adminRouter.HandleFunc("/webhooks/{id}/deliveries/{deliveryId}/redeliver", webhookController.Redeliver).Methods("POST")
 }
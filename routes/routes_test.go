@@ -0,0 +1,489 @@
+package routes
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"my_blog/config"
+	"my_blog/middleware"
+	"my_blog/models"
+	"my_blog/ratelimit"
+	"my_blog/storage"
+	"my_blog/utils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+)
+
+type access int
+
+const (
+	public access = iota // 无需认证（含可选认证）
+	authed               // 需要登录
+	admin                // 需要管理员
+)
+
+// route 已注册的路由，template 与 InitializeRoutes 中的路径模板一致
+type route struct {
+	method   string
+	template string
+	access   access
+}
+
+// routeTable 所有已注册路由及其访问级别，新增路由时需同步补充
+var routeTable = []route{
+	{"GET", "/uploads/", public},
+	{"HEAD", "/uploads/", public},
+	{"GET", "/metrics", public},
+	{"GET", "/healthz", public},
+	{"GET", "/readyz", public},
+	{"POST", "/register", public},
+	{"POST", "/login", public},
+	{"POST", "/email/verify", public},
+	{"POST", "/password/forgot", public},
+	{"POST", "/password/reset", public},
+	{"GET", "/articles", public},
+	{"GET", "/articles/{id}", public},
+	{"GET", "/categories", public},
+	{"GET", "/categories/{id}", public},
+	{"GET", "/categories/{id}/articles", public},
+	{"GET", "/authors/{username}", public},
+	{"GET", "/authors/{username}/articles", public},
+	{"GET", "/events", public},
+
+	{"GET", "/users/me", authed},
+	{"PUT", "/users/me", authed},
+	{"PATCH", "/users/me", authed},
+	{"PUT", "/users/me/password", authed},
+	{"PUT", "/users/me/profile", authed},
+	{"GET", "/users/me/notification-preferences", authed},
+	{"PUT", "/users/me/notification-preferences", authed},
+	{"GET", "/notifications", authed},
+	{"PUT", "/notifications/read-all", authed},
+	{"PUT", "/notifications/{id}/read", authed},
+	{"POST", "/users/me/email-verification", authed},
+	{"GET", "/users/me/bookmarks", authed},
+	{"GET", "/users/me/reading-lists", authed},
+	{"POST", "/users/me/reading-lists", authed},
+	{"GET", "/users/me/reading-lists/{id}", authed},
+	{"PUT", "/users/me/reading-lists/{id}", authed},
+	{"DELETE", "/users/me/reading-lists/{id}", authed},
+	{"PUT", "/users/me/reading-lists/{id}/order", authed},
+	{"PUT", "/users/me/reading-lists/{id}/articles/{articleId}", authed},
+	{"DELETE", "/users/me/reading-lists/{id}/articles/{articleId}", authed},
+	{"POST", "/users/{id}/background-image", authed},
+	{"PUT", "/users/{id}", authed},
+	{"PATCH", "/users/{id}", authed},
+	{"PUT", "/users/{id}/follow", authed},
+	{"DELETE", "/users/{id}/follow", authed},
+	{"PUT", "/categories/{id}/follow", authed},
+	{"DELETE", "/categories/{id}/follow", authed},
+	{"GET", "/feed", authed},
+	{"POST", "/articles", authed},
+	{"PUT", "/articles/{id}", authed},
+	{"PATCH", "/articles/{id}", authed},
+	{"DELETE", "/articles/{id}", authed},
+	{"POST", "/articles/{id}/reactions", authed},
+	{"PUT", "/articles/{id}/bookmark", authed},
+	{"DELETE", "/articles/{id}/bookmark", authed},
+	{"POST", "/articles/{id}/restore", authed},
+	{"POST", "/uploads", authed},
+	{"GET", "/articles/{id}/comments", authed},
+	{"POST", "/articles/{id}/comments", authed},
+	{"PUT", "/comments/{id}", authed},
+	{"DELETE", "/comments/{id}", authed},
+	{"POST", "/comments/{id}/reactions", authed},
+	{"POST", "/comments/{id}/restore", authed},
+	{"GET", "/trash/articles", authed},
+	{"GET", "/trash/comments", authed},
+
+	{"GET", "/users", admin},
+	{"DELETE", "/users/{id}", admin},
+	{"PUT", "/users/{id}/role", admin},
+	{"PUT", "/users/{id}/status", admin},
+	{"POST", "/categories", admin},
+	{"PUT", "/categories/{id}", admin},
+	{"DELETE", "/categories/{id}", admin},
+	{"GET", "/webhooks", admin},
+	{"POST", "/webhooks", admin},
+	{"PUT", "/webhooks/{id}", admin},
+	{"DELETE", "/webhooks/{id}", admin},
+	{"GET", "/webhooks/{id}/deliveries", admin},
+	{"POST", "/webhooks/{id}/deliveries/{deliveryId}/redeliver", admin},
+}
+
+// path 将路径模板中的变量替换为具体值
+func (rt route) path() string {
+	if rt.template == "/uploads/" {
+		return "/uploads/ab/test.jpg"
+	}
+	return strings.NewReplacer(
+		"{id}", "1",
+		"{articleId}", "2",
+		"{deliveryId}", "3",
+		"{username}", "alice",
+	).Replace(rt.template)
+}
+
+func (rt route) String() string {
+	return rt.method + " " + rt.template
+}
+
+const userStateQuery = "SELECT role_id, locale, status, status_until FROM users WHERE id = ?"
+
+// testUserID 测试请求的用户ID，与路径中的 {id} 相同，/users/{id} 下的接口操作的是本人
+const testUserID = 1
+
+// newTestRouter 使用 sqlmock 作为数据库、临时目录作为上传存储，按 main 中的方式构建路由
+func newTestRouter(t *testing.T) (http.Handler, *mux.Router, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+
+	uploadDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(uploadDir, "ab"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(uploadDir, "ab", "test.jpg"), []byte("jpeg"), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	oldDB, oldStorage, oldStore, oldLogger := config.DB, config.Storage, config.RateLimitStore, slog.Default()
+	config.DB = db
+	config.Storage = storage.NewLocalStorage(uploadDir, config.UploadURLPrefix, "test")
+	config.RateLimitStore = ratelimit.NewMemoryStore()
+	slog.SetDefault(logger)
+	t.Cleanup(func() {
+		db.Close()
+		config.DB, config.Storage, config.RateLimitStore = oldDB, oldStorage, oldStore
+		slog.SetDefault(oldLogger)
+	})
+
+	router := mux.NewRouter()
+	InitializeRoutes(router, logger)
+	return middleware.LocaleMiddleware(router), router, mock
+}
+
+// expectUser 为下一次认证准备用户状态查询的结果
+func expectUser(mock sqlmock.Sqlmock, userID, roleID int, status string) {
+	mock.ExpectQuery(regexp.QuoteMeta(userStateQuery)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"role_id", "locale", "status", "status_until"}).
+			AddRow(roleID, nil, status, nil))
+}
+
+func token(t *testing.T, userID int) string {
+	t.Helper()
+	tok, err := middleware.GenerateToken(userID, "tester", time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	return "Bearer " + tok
+}
+
+func serve(handler http.Handler, method, path, auth string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	// 实时推送为长连接，取消的上下文使其写出响应头后立即返回
+	if path == "/events" {
+		ctx, cancel := context.WithCancel(req.Context())
+		cancel()
+		req = req.WithContext(ctx)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// reachedHandler 请求是否通过了路由匹配和权限检查，到达了控制器
+func reachedHandler(code int) bool {
+	switch code {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusMethodNotAllowed:
+		return false
+	}
+	return true
+}
+
+func TestRouteTableCoversRegisteredRoutes(t *testing.T) {
+	_, router, _ := newTestRouter(t)
+
+	registered := map[string]bool{}
+	err := router.Walk(func(r *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := r.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := r.GetMethods()
+		if err != nil {
+			return nil
+		}
+		for _, method := range methods {
+			registered[method+" "+template] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+
+	listed := map[string]bool{}
+	for _, rt := range routeTable {
+		listed[rt.String()] = true
+	}
+
+	var missing, stale []string
+	for r := range registered {
+		if !listed[r] {
+			missing = append(missing, r)
+		}
+	}
+	for r := range listed {
+		if !registered[r] {
+			stale = append(stale, r)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+	if len(missing) > 0 {
+		t.Errorf("routes missing from routeTable: %v", missing)
+	}
+	if len(stale) > 0 {
+		t.Errorf("routeTable lists unregistered routes: %v", stale)
+	}
+}
+
+func TestPublicRoutesAllowAnonymous(t *testing.T) {
+	handler, _, _ := newTestRouter(t)
+	for _, rt := range routeTable {
+		if rt.access != public {
+			continue
+		}
+		t.Run(rt.String(), func(t *testing.T) {
+			rec := serve(handler, rt.method, rt.path(), "")
+			if !reachedHandler(rec.Code) {
+				t.Errorf("status = %d, body = %s", rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestProtectedRoutesRequireToken(t *testing.T) {
+	handler, _, mock := newTestRouter(t)
+	for _, rt := range routeTable {
+		if rt.access == public {
+			continue
+		}
+		t.Run(rt.String(), func(t *testing.T) {
+			if rec := serve(handler, rt.method, rt.path(), ""); rec.Code != http.StatusUnauthorized {
+				t.Errorf("without token: status = %d, want 401", rec.Code)
+			}
+			if rec := serve(handler, rt.method, rt.path(), "Bearer not-a-jwt"); rec.Code != http.StatusUnauthorized {
+				t.Errorf("invalid token: status = %d, want 401", rec.Code)
+			}
+		})
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProtectedRoutesCheckAccountStatus(t *testing.T) {
+	handler, _, mock := newTestRouter(t)
+	tests := []struct {
+		status string
+		want   int
+	}{
+		{models.UserStatusDeleted, http.StatusUnauthorized},
+		{models.UserStatusSuspended, http.StatusForbidden},
+		{models.UserStatusBanned, http.StatusForbidden},
+	}
+	for _, rt := range routeTable {
+		if rt.access == public {
+			continue
+		}
+		for _, tt := range tests {
+			expectUser(mock, testUserID, utils.RoleUser, tt.status)
+			if rec := serve(handler, rt.method, rt.path(), token(t, testUserID)); rec.Code != tt.want {
+				t.Errorf("%s as %s user: status = %d, want %d", rt, tt.status, rec.Code, tt.want)
+			}
+		}
+	}
+
+	// 令牌有效但用户已不存在
+	mock.ExpectQuery(regexp.QuoteMeta(userStateQuery)).WithArgs(8).
+		WillReturnRows(sqlmock.NewRows([]string{"role_id", "locale", "status", "status_until"}))
+	if rec := serve(handler, "GET", "/users/me", token(t, 8)); rec.Code != http.StatusUnauthorized {
+		t.Errorf("unknown user: status = %d, want 401", rec.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRoutesEnforceRoles(t *testing.T) {
+	handler, _, mock := newTestRouter(t)
+	roles := []struct {
+		name   string
+		roleID int
+		level  access
+	}{
+		{"guest", utils.RoleGuest, authed},
+		{"user", utils.RoleUser, authed},
+		{"admin", utils.RoleAdmin, admin},
+	}
+	for _, role := range roles {
+		for _, rt := range routeTable {
+			if rt.access == public {
+				continue
+			}
+			t.Run(role.name+" "+rt.String(), func(t *testing.T) {
+				expectUser(mock, testUserID, role.roleID, models.UserStatusActive)
+				rec := serve(handler, rt.method, rt.path(), token(t, testUserID))
+				if rt.access <= role.level {
+					if !reachedHandler(rec.Code) {
+						t.Errorf("status = %d, want handler to run; body = %s", rec.Code, rec.Body)
+					}
+				} else if rec.Code != http.StatusForbidden {
+					t.Errorf("status = %d, want 403", rec.Code)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Error(err)
+				}
+			})
+		}
+	}
+}
+
+func TestRoutesStatusCodes(t *testing.T) {
+	handler, _, mock := newTestRouter(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		setup  func()
+		auth   bool
+		want   int
+	}{
+		{name: "liveness", method: "GET", path: "/healthz", want: http.StatusOK},
+		{name: "readiness", method: "GET", path: "/readyz", want: http.StatusOK},
+		{name: "metrics", method: "GET", path: "/metrics", want: http.StatusOK},
+		{name: "upload file", method: "GET", path: "/uploads/ab/test.jpg", want: http.StatusOK},
+		{name: "upload head", method: "HEAD", path: "/uploads/ab/test.jpg", want: http.StatusOK},
+		{name: "missing upload", method: "GET", path: "/uploads/ab/missing.jpg", want: http.StatusNotFound},
+		// mux 将含 .. 的路径重定向到规范路径，不会读取上传目录之外的文件
+		{name: "upload traversal", method: "GET", path: "/uploads/../config/config.go", want: http.StatusMovedPermanently},
+		{name: "bad upload signature", method: "GET", path: "/uploads/ab/test.jpg?expires=1&signature=x", want: http.StatusForbidden},
+		{name: "events stream", method: "GET", path: "/events", want: http.StatusOK},
+		{name: "invalid article id", method: "GET", path: "/articles/abc", want: http.StatusBadRequest},
+		{name: "invalid category id", method: "GET", path: "/categories/abc", want: http.StatusBadRequest},
+		{name: "register without body", method: "POST", path: "/register", want: http.StatusBadRequest},
+		{name: "login without body", method: "POST", path: "/login", want: http.StatusBadRequest},
+		{
+			name: "categories", method: "GET", path: "/categories",
+			setup: func() {
+				mock.ExpectQuery("SELECT (.+) FROM categories").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description"}).AddRow(1, "Go", "Go 语言"))
+			},
+			want: http.StatusOK,
+		},
+		{name: "current user", method: "GET", path: "/users/me", auth: true,
+			setup: func() {
+				mock.ExpectQuery("SELECT (.+) FROM users").WillReturnError(io.ErrUnexpectedEOF)
+			},
+			want: http.StatusInternalServerError,
+		},
+		{name: "create article without body", method: "POST", path: "/articles", auth: true, want: http.StatusBadRequest},
+		{name: "upload without form", method: "POST", path: "/uploads", auth: true, want: http.StatusBadRequest},
+		{name: "unknown path", method: "GET", path: "/no-such-route", want: http.StatusNotFound},
+		// 需要认证的子路由以空前缀匹配所有路径，方法不匹配时返回404而非405
+		{name: "wrong method", method: "DELETE", path: "/healthz", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := ""
+			if tt.auth {
+				expectUser(mock, testUserID, utils.RoleUser, models.UserStatusActive)
+				auth = token(t, testUserID)
+			}
+			if tt.setup != nil {
+				tt.setup()
+			}
+			rec := serve(handler, tt.method, tt.path, auth)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d; body = %s", rec.Code, tt.want, rec.Body)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestUpdateArticleForbiddenForOtherUsers(t *testing.T) {
+	handler, _, mock := newTestRouter(t)
+	for _, method := range []string{"PUT", "PATCH"} {
+		t.Run(method, func(t *testing.T) {
+			expectUser(mock, testUserID, utils.RoleUser, models.UserStatusActive)
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT image_path FROM articles WHERE id = ? AND deleted_at IS NULL")).WithArgs(5).
+				WillReturnRows(sqlmock.NewRows([]string{"image_path"}).AddRow(nil))
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT title, author_id FROM articles WHERE id = ?")).WithArgs(5).
+				WillReturnRows(sqlmock.NewRows([]string{"title", "author_id"}).AddRow("标题", testUserID+1))
+
+			req := httptest.NewRequest(method, "/articles/5", strings.NewReader(`{"title":"新标题","content":"内容"}`))
+			req.Header.Set("Authorization", token(t, testUserID))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want 403; body = %s", rec.Code, rec.Body)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestUpdateProfileReturnsUser(t *testing.T) {
+	handler, _, mock := newTestRouter(t)
+	expectUser(mock, testUserID, utils.RoleUser, models.UserStatusActive)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users")).
+		WithArgs("你好", "", "", sqlmock.AnyArg(), testUserID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, username, email")).WithArgs(testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "username", "email", "image_data", "background_image", "role_id", "locale", "email_verified",
+			"status", "status_reason", "status_until", "bio", "website", "location", "social_links",
+		}).AddRow(testUserID, "alice", "alice@example.com", "", "", utils.RoleUser, "", true,
+			models.UserStatusActive, "", nil, "你好", "", "", ""))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM user_follows")).WithArgs(testUserID, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"followers", "following"}).AddRow(0, 0))
+
+	// 响应与 GET /users/me 一样经过序列化，支持按 fields 筛选字段
+	req := httptest.NewRequest("PUT", "/users/me/profile?fields=id,username,bio", strings.NewReader(`{"bio":"你好"}`))
+	req.Header.Set("Authorization", token(t, testUserID))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body = %s", rec.Code, rec.Body)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `"data":{"bio":"你好","id":1,"username":"alice"}`) {
+		t.Errorf("body = %s", body)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
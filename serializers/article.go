@@ -0,0 +1,128 @@
+package serializers
+
+import (
+	"my_blog/models"
+	"time"
+)
+
+// CategoryResponse 分类
+type CategoryResponse struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Category 生成分类响应
+func Category(category *models.Category) CategoryResponse {
+	return CategoryResponse{ID: category.ID, Name: category.Name, Description: category.Description}
+}
+
+// Categories 生成分类列表响应
+func Categories(categories []models.Category) []CategoryResponse {
+	resp := make([]CategoryResponse, len(categories))
+	for i := range categories {
+		resp[i] = Category(&categories[i])
+	}
+	return resp
+}
+
+// ArticleResponse 文章，反应统计只在加载后输出
+type ArticleResponse struct {
+	ID        int              `json:"id"`
+	Title     string           `json:"title"`
+	Content   string           `json:"content"`
+	Author    string           `json:"author"`
+	AuthorID  int              `json:"author_id,omitempty"`
+	CreateAt  time.Time        `json:"create_at"`
+	ImagePath *string          `json:"image_path,omitempty"`
+	Category  CategoryResponse `json:"category"`
+	Views     int              `json:"views"`
+
+	ImageVariants *models.ImageVariants `json:"image_variants,omitempty"`
+
+	*models.ReactionSummary
+}
+
+// Article 生成文章响应
+func Article(article *models.Article) ArticleResponse {
+	return ArticleResponse{
+		ID:              article.ID,
+		Title:           article.Title,
+		Content:         article.Content,
+		Author:          article.Author,
+		AuthorID:        article.AuthorID,
+		CreateAt:        article.CreateAt,
+		ImagePath:       article.ImagePath,
+		Category:        Category(&article.Category),
+		Views:           article.Views,
+		ImageVariants:   article.ImageVariants,
+		ReactionSummary: article.ReactionSummary,
+	}
+}
+
+// Articles 生成文章列表响应
+func Articles(articles []models.Article) []ArticleResponse {
+	resp := make([]ArticleResponse, len(articles))
+	for i := range articles {
+		resp[i] = Article(&articles[i])
+	}
+	return resp
+}
+
+// ArticleListResponse 分页的文章列表
+type ArticleListResponse struct {
+	Items []ArticleResponse `json:"items"`
+	Total int               `json:"total"`
+}
+
+// ArticleList 生成分页文章列表响应
+func ArticleList(list *models.ArticleList) ArticleListResponse {
+	return ArticleListResponse{Items: Articles(list.Items), Total: list.Total}
+}
+
+// ArticleFeedResponse 游标分页的文章列表，NextCursor 为空表示没有更多
+type ArticleFeedResponse struct {
+	Items      []ArticleResponse `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// ArticleFeed 生成动态响应
+func ArticleFeed(feed *models.ArticleFeed) ArticleFeedResponse {
+	return ArticleFeedResponse{Items: Articles(feed.Items), NextCursor: feed.NextCursor}
+}
+
+// ReadingListResponse 阅读列表，Articles 只在获取单个列表时返回
+type ReadingListResponse struct {
+	ID           int               `json:"id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	ArticleCount int               `json:"article_count"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	Articles     []ArticleResponse `json:"articles,omitempty"`
+}
+
+// ReadingList 生成阅读列表响应
+func ReadingList(list *models.ReadingList) ReadingListResponse {
+	resp := ReadingListResponse{
+		ID:           list.ID,
+		Name:         list.Name,
+		Description:  list.Description,
+		ArticleCount: list.ArticleCount,
+		CreatedAt:    list.CreatedAt,
+		UpdatedAt:    list.UpdatedAt,
+	}
+	if list.Articles != nil {
+		resp.Articles = Articles(list.Articles)
+	}
+	return resp
+}
+
+// ReadingLists 生成阅读列表的列表响应
+func ReadingLists(lists []models.ReadingList) []ReadingListResponse {
+	resp := make([]ReadingListResponse, len(lists))
+	for i := range lists {
+		resp[i] = ReadingList(&lists[i])
+	}
+	return resp
+}
@@ -0,0 +1,44 @@
+package serializers
+
+import (
+	"my_blog/models"
+	"time"
+)
+
+// CommentResponse 评论，反应统计只在加载后输出
+type CommentResponse struct {
+	ID        int        `json:"id"`
+	ArticleID int        `json:"article_id"`
+	Content   string     `json:"content"`
+	Author    string     `json:"author"`
+	CreateAt  time.Time  `json:"create_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	UserID    int        `json:"user_id,omitempty"`
+	ParentID  *int       `json:"parent_id,omitempty"`
+
+	*models.ReactionSummary
+}
+
+// Comment 生成评论响应
+func Comment(comment *models.Comment) CommentResponse {
+	return CommentResponse{
+		ID:              comment.ID,
+		ArticleID:       comment.ArticleID,
+		Content:         comment.Content,
+		Author:          comment.Author,
+		CreateAt:        comment.CreateAt,
+		UpdatedAt:       comment.UpdatedAt,
+		UserID:          comment.UserID,
+		ParentID:        comment.ParentID,
+		ReactionSummary: comment.ReactionSummary,
+	}
+}
+
+// Comments 生成评论列表响应
+func Comments(comments []models.Comment) []CommentResponse {
+	resp := make([]CommentResponse, len(comments))
+	for i := range comments {
+		resp[i] = Comment(&comments[i])
+	}
+	return resp
+}
@@ -0,0 +1,58 @@
+package serializers
+
+import (
+	"my_blog/models"
+	"time"
+)
+
+// NotificationResponse 站内通知
+type NotificationResponse struct {
+	ID        int64                  `json:"id"`
+	Type      string                 `json:"type"`
+	ArticleID *int                   `json:"article_id,omitempty"`
+	CommentID *int                   `json:"comment_id,omitempty"`
+	Data      map[string]interface{} `json:"data"`
+	Read      bool                   `json:"read"`
+	CreateAt  time.Time              `json:"create_at"`
+}
+
+// NotificationListResponse 站内通知列表及未读数量
+type NotificationListResponse struct {
+	Items       []NotificationResponse `json:"items"`
+	UnreadCount int                    `json:"unread_count"`
+}
+
+// NotificationList 生成站内通知列表响应
+func NotificationList(list *models.NotificationList) NotificationListResponse {
+	items := make([]NotificationResponse, len(list.Items))
+	for i, n := range list.Items {
+		items[i] = NotificationResponse{
+			ID:        n.ID,
+			Type:      n.Type,
+			ArticleID: n.ArticleID,
+			CommentID: n.CommentID,
+			Data:      n.Data,
+			Read:      n.Read,
+			CreateAt:  n.CreateAt,
+		}
+	}
+	return NotificationListResponse{Items: items, UnreadCount: list.UnreadCount}
+}
+
+// NotificationPreferencesResponse 邮件通知设置
+type NotificationPreferencesResponse struct {
+	EmailComment    bool `json:"email_comment"`
+	EmailReply      bool `json:"email_reply"`
+	EmailModeration bool `json:"email_moderation"`
+	EmailAccount    bool `json:"email_account"`
+}
+
+// NotificationPreferences 生成邮件通知设置响应
+func NotificationPreferences(prefs *models.NotificationPreferences) NotificationPreferencesResponse {
+	return NotificationPreferencesResponse{
+		EmailComment:    prefs.EmailComment,
+		EmailReply:      prefs.EmailReply,
+		EmailModeration: prefs.EmailModeration,
+		EmailAccount:    prefs.EmailAccount,
+	}
+}
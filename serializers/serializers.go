@@ -0,0 +1,94 @@
+// Package serializers 将领域模型转换为API响应：每种资源有独立的响应类型，
+// 按查看者的身份决定可见字段，并支持通过 ?fields= 只返回需要的字段
+package serializers
+
+import (
+	"bytes"
+	"encoding/json"
+	"my_blog/middleware"
+	"my_blog/utils"
+	"net/http"
+	"strings"
+)
+
+// Viewer 查看响应的用户，未登录时 UserID 和 RoleID 为0
+type Viewer struct {
+	UserID int
+	RoleID int
+}
+
+// ViewerFrom 从请求上下文读取当前用户及其角色
+func ViewerFrom(r *http.Request) Viewer {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+	roleID, _ := middleware.RoleFromContext(r.Context())
+	return Viewer{UserID: userID, RoleID: roleID}
+}
+
+// IsAdmin 是否为管理员
+func (v Viewer) IsAdmin() bool {
+	return v.RoleID == utils.RoleAdmin
+}
+
+// IsSelf 是否为该用户本人
+func (v Viewer) IsSelf(userID int) bool {
+	return v.UserID != 0 && v.UserID == userID
+}
+
+// Fields 解析 fields 查询参数（逗号分隔的字段名），未指定时返回nil表示返回全部字段
+func Fields(r *http.Request) []string {
+	var fields []string
+	for _, f := range strings.Split(r.URL.Query().Get("fields"), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Sparse 只保留 fields 列出的字段：data 为对象时筛选对象本身，为数组时筛选每个元素，
+// 为分页列表（含 items 数组的对象）时筛选 items 中的元素。fields 为空时原样返回，不存在的字段名被忽略
+func Sparse(data interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 || data == nil {
+		return data, nil
+	}
+	keep := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		keep[f] = true
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	// 保留数字的原始写法，避免大整数转为浮点数
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return pick(generic, keep, true), nil
+}
+
+// pick 递归筛选字段，list 为true时允许把含 items 数组的对象当作分页列表
+func pick(v interface{}, keep map[string]bool, list bool) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = pick(v[i], keep, false)
+		}
+		return v
+	case map[string]interface{}:
+		if items, ok := v["items"].([]interface{}); ok && list {
+			v["items"] = pick(items, keep, false)
+			return v
+		}
+		for k := range v {
+			if !keep[k] {
+				delete(v, k)
+			}
+		}
+		return v
+	}
+	return v
+}
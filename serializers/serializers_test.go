@@ -0,0 +1,128 @@
+package serializers
+
+import (
+	"encoding/json"
+	"my_blog/models"
+	"my_blog/utils"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type sparseItem struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type sparseList struct {
+	Items []sparseItem `json:"items"`
+	Total int          `json:"total"`
+}
+
+func TestSparse(t *testing.T) {
+	item := sparseItem{ID: 1, Title: "t", Body: "b"}
+	tests := []struct {
+		name   string
+		data   interface{}
+		fields []string
+		want   string
+	}{
+		{"no fields", item, nil, `{"id":1,"title":"t","body":"b"}`},
+		{"nil data", nil, []string{"id"}, `null`},
+		{"object", item, []string{"id", "title"}, `{"id":1,"title":"t"}`},
+		{"unknown field ignored", item, []string{"id", "missing"}, `{"id":1}`},
+		{"only unknown fields", item, []string{"missing"}, `{}`},
+		{"array", []sparseItem{item, {ID: 2, Title: "u"}}, []string{"title"}, `[{"title":"t"},{"title":"u"}]`},
+		{"empty array", []sparseItem{}, []string{"title"}, `[]`},
+		{"paginated list", sparseList{Items: []sparseItem{item}, Total: 1}, []string{"id"}, `{"items":[{"id":1}],"total":1}`},
+		{"large number kept exact", sparseItem{ID: 9007199254740993}, []string{"id"}, `{"id":9007199254740993}`},
+		{
+			"nested items only unwrapped at top level",
+			[]map[string]interface{}{{"id": 1, "items": []int{1}}},
+			[]string{"id"},
+			`[{"id":1}]`,
+		},
+		{"scalar", 42, []string{"id"}, `42`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sparse(tt.data, tt.fields)
+			if err != nil {
+				t.Fatalf("Sparse: %v", err)
+			}
+			raw, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 筛选后的对象为 map，编码时键按字母排序
+			if string(raw) != tt.want {
+				t.Errorf("Sparse = %s, want %s", raw, tt.want)
+			}
+		})
+	}
+}
+
+func TestSparseUnmarshalable(t *testing.T) {
+	if _, err := Sparse(map[string]interface{}{"ch": make(chan int)}, []string{"ch"}); err == nil {
+		t.Error("Sparse(chan) succeeded, want error")
+	}
+}
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", nil},
+		{"?fields=", nil},
+		{"?fields=id", []string{"id"}},
+		{"?fields=id,%20title%20,,body", []string{"id", "title", "body"}},
+		{"?fields=,", nil},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/articles"+tt.query, nil)
+		if got := Fields(r); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Fields(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestViewer(t *testing.T) {
+	tests := []struct {
+		name    string
+		viewer  Viewer
+		admin   bool
+		selfOf1 bool
+	}{
+		{"anonymous", Viewer{}, false, false},
+		{"admin", Viewer{UserID: 2, RoleID: utils.RoleAdmin}, true, false},
+		{"self", Viewer{UserID: 1, RoleID: utils.RoleUser}, false, true},
+	}
+	for _, tt := range tests {
+		if got := tt.viewer.IsAdmin(); got != tt.admin {
+			t.Errorf("%s: IsAdmin = %v", tt.name, got)
+		}
+		if got := tt.viewer.IsSelf(1); got != tt.selfOf1 {
+			t.Errorf("%s: IsSelf(1) = %v", tt.name, got)
+		}
+	}
+	if (Viewer{}).IsSelf(0) {
+		t.Error("anonymous viewer is self of user 0")
+	}
+}
+
+func TestWebhookSecretOnlyOnCreate(t *testing.T) {
+	hook := &models.Webhook{ID: 1, URL: "https://example.com/hook", Secret: "whsec", Events: []string{"article.created"}}
+	if got := Webhook(hook).Secret; got != "" {
+		t.Errorf("Webhook exposed secret %q", got)
+	}
+	for _, resp := range Webhooks([]models.Webhook{*hook}) {
+		if resp.Secret != "" {
+			t.Errorf("Webhooks exposed secret %q", resp.Secret)
+		}
+	}
+	if got := CreatedWebhook(hook).Secret; got != "whsec" {
+		t.Errorf("CreatedWebhook secret = %q", got)
+	}
+}
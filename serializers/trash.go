@@ -0,0 +1,51 @@
+package serializers
+
+import "my_blog/models"
+
+// TrashedArticleResponse 回收站中的文章及其删除信息
+type TrashedArticleResponse struct {
+	ArticleResponse
+	models.Trashed
+}
+
+// TrashedArticleListResponse 分页的回收站文章列表
+type TrashedArticleListResponse struct {
+	Items []TrashedArticleResponse `json:"items"`
+	Total int                      `json:"total"`
+}
+
+// TrashedArticleList 生成回收站文章列表响应
+func TrashedArticleList(list *models.TrashedArticleList) TrashedArticleListResponse {
+	items := make([]TrashedArticleResponse, len(list.Items))
+	for i := range list.Items {
+		items[i] = TrashedArticleResponse{
+			ArticleResponse: Article(&list.Items[i].Article),
+			Trashed:         list.Items[i].Trashed,
+		}
+	}
+	return TrashedArticleListResponse{Items: items, Total: list.Total}
+}
+
+// TrashedCommentResponse 回收站中的评论及其删除信息
+type TrashedCommentResponse struct {
+	CommentResponse
+	models.Trashed
+}
+
+// TrashedCommentListResponse 分页的回收站评论列表
+type TrashedCommentListResponse struct {
+	Items []TrashedCommentResponse `json:"items"`
+	Total int                      `json:"total"`
+}
+
+// TrashedCommentList 生成回收站评论列表响应
+func TrashedCommentList(list *models.TrashedCommentList) TrashedCommentListResponse {
+	items := make([]TrashedCommentResponse, len(list.Items))
+	for i := range list.Items {
+		items[i] = TrashedCommentResponse{
+			CommentResponse: Comment(&list.Items[i].Comment),
+			Trashed:         list.Items[i].Trashed,
+		}
+	}
+	return TrashedCommentListResponse{Items: items, Total: list.Total}
+}
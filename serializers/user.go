@@ -0,0 +1,99 @@
+package serializers
+
+import (
+	"my_blog/models"
+	"time"
+)
+
+// UserResponse 用户信息。邮箱、验证状态、角色和账号状态只对本人和管理员可见，偏好语言只对本人可见
+type UserResponse struct {
+	ID              int    `json:"id"`
+	Username        string `json:"username"`
+	ImageData       string `json:"image_data"`
+	BackgroundImage string `json:"background_image"`
+	models.Profile
+	FollowerCount  int `json:"follower_count"`
+	FollowingCount int `json:"following_count"`
+
+	Email         *string `json:"email,omitempty"`
+	EmailVerified *bool   `json:"email_verified,omitempty"`
+	RoleID        *int    `json:"role_id,omitempty"`
+	Locale        string  `json:"locale,omitempty"`
+
+	Status       string     `json:"status,omitempty"`
+	StatusReason string     `json:"status_reason,omitempty"`
+	StatusUntil  *time.Time `json:"status_until,omitempty"`
+
+	ImageVariants      *models.ImageVariants `json:"image_variants,omitempty"`
+	BackgroundVariants *models.ImageVariants `json:"background_variants,omitempty"`
+}
+
+// User 按查看者的身份生成用户响应
+func User(user *models.User, v Viewer) UserResponse {
+	resp := UserResponse{
+		ID:                 user.ID,
+		Username:           user.Username,
+		ImageData:          user.ImageData,
+		BackgroundImage:    user.BackgroundImage,
+		Profile:            user.Profile,
+		FollowerCount:      user.FollowerCount,
+		FollowingCount:     user.FollowingCount,
+		ImageVariants:      user.ImageVariants,
+		BackgroundVariants: user.BackgroundVariants,
+	}
+	if v.IsSelf(user.ID) || v.IsAdmin() {
+		resp.Email = &user.Email
+		resp.EmailVerified = &user.EmailVerified
+		resp.RoleID = &user.RoleID
+		// 暂停到期后按正常状态返回，不再展示原因和截止时间
+		resp.Status = models.EffectiveStatus(user.Status, user.StatusUntil, time.Now())
+		if resp.Status != models.UserStatusActive {
+			resp.StatusReason = user.StatusReason
+			resp.StatusUntil = user.StatusUntil
+		}
+	}
+	if v.IsSelf(user.ID) {
+		resp.Locale = user.Locale
+	}
+	return resp
+}
+
+// Users 按查看者的身份生成用户列表响应
+func Users(users []models.User, v Viewer) []UserResponse {
+	resp := make([]UserResponse, len(users))
+	for i := range users {
+		resp[i] = User(&users[i], v)
+	}
+	return resp
+}
+
+// AuthorResponse 作者公开主页，只包含可公开的资料
+type AuthorResponse struct {
+	ID              int    `json:"id"`
+	Username        string `json:"username"`
+	ImageData       string `json:"image_data"`
+	BackgroundImage string `json:"background_image"`
+	models.Profile
+	Stats        models.AuthorStats `json:"stats"`
+	FollowedByMe bool               `json:"followed_by_me"`
+	Articles     []ArticleResponse  `json:"articles"`
+
+	ImageVariants      *models.ImageVariants `json:"image_variants,omitempty"`
+	BackgroundVariants *models.ImageVariants `json:"background_variants,omitempty"`
+}
+
+// Author 生成作者主页响应
+func Author(author *models.AuthorProfile) AuthorResponse {
+	return AuthorResponse{
+		ID:                 author.ID,
+		Username:           author.Username,
+		ImageData:          author.ImageData,
+		BackgroundImage:    author.BackgroundImage,
+		Profile:            author.Profile,
+		Stats:              author.Stats,
+		FollowedByMe:       author.FollowedByMe,
+		Articles:           Articles(author.Articles),
+		ImageVariants:      author.ImageVariants,
+		BackgroundVariants: author.BackgroundVariants,
+	}
+}
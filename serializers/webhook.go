@@ -0,0 +1,80 @@
+package serializers
+
+import (
+	"encoding/json"
+	"my_blog/models"
+	"time"
+)
+
+// WebhookResponse Webhook 订阅，签名密钥只在创建时返回
+type WebhookResponse struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Webhook 生成 Webhook 响应，不含签名密钥
+func Webhook(hook *models.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:        hook.ID,
+		URL:       hook.URL,
+		Events:    hook.Events,
+		Active:    hook.Active,
+		CreatedAt: hook.CreatedAt,
+	}
+}
+
+// CreatedWebhook 生成新建 Webhook 的响应，包含签名密钥
+func CreatedWebhook(hook *models.Webhook) WebhookResponse {
+	resp := Webhook(hook)
+	resp.Secret = hook.Secret
+	return resp
+}
+
+// Webhooks 生成 Webhook 列表响应
+func Webhooks(hooks []models.Webhook) []WebhookResponse {
+	resp := make([]WebhookResponse, len(hooks))
+	for i := range hooks {
+		resp[i] = Webhook(&hooks[i])
+	}
+	return resp
+}
+
+// WebhookDeliveryResponse Webhook 的一次投递及其结果
+type WebhookDeliveryResponse struct {
+	ID             int64           `json:"id"`
+	WebhookID      int             `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int            `json:"response_status"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+}
+
+// WebhookDeliveries 生成投递记录列表响应
+func WebhookDeliveries(deliveries []models.WebhookDelivery) []WebhookDeliveryResponse {
+	resp := make([]WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		resp[i] = WebhookDeliveryResponse{
+			ID:             d.ID,
+			WebhookID:      d.WebhookID,
+			Event:          d.Event,
+			Payload:        d.Payload,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			ResponseStatus: d.ResponseStatus,
+			LastError:      d.LastError,
+			NextAttemptAt:  d.NextAttemptAt,
+			CreatedAt:      d.CreatedAt,
+			DeliveredAt:    d.DeliveredAt,
+		}
+	}
+	return resp
+}
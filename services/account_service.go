@@ -0,0 +1,239 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"log/slog"
+	"my_blog/config"
+	"net/url"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 用户令牌用途
+const (
+	tokenVerifyEmail   = "verify_email"
+	tokenResetPassword = "reset_password"
+)
+
+// AccountService 邮箱验证、找回密码和修改密码，邮件写入发送队列
+type AccountService struct {
+	notifications *NotificationService
+	logger        *slog.Logger
+}
+
+// NewAccountService 创建账号服务
+func NewAccountService(logger *slog.Logger) *AccountService {
+	return &AccountService{notifications: NewNotificationService(logger), logger: logger}
+}
+
+// QueueVerificationEmail 注册后发送邮箱验证邮件，失败只记录日志，不影响注册结果
+func (s *AccountService) QueueVerificationEmail(userID int) {
+	if err := s.SendVerificationEmail(userID); err != nil {
+		s.logger.Error("queue verification email failed", "user_id", userID, "error", err)
+	}
+}
+
+// SendVerificationEmail 生成邮箱验证令牌并发送验证链接，邮箱已验证时返回 ErrEmailVerified
+func (s *AccountService) SendVerificationEmail(userID int) error {
+	var user mailRecipient
+	var locale sql.NullString
+	var verifiedAt sql.NullTime
+	err := config.DB.QueryRow(`
+		SELECT id, username, email, locale, email_verified_at FROM users WHERE id = ?
+	`, userID).Scan(&user.id, &user.username, &user.email, &locale, &verifiedAt)
+	if err == sql.ErrNoRows {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if verifiedAt.Valid {
+		return ErrEmailVerified
+	}
+	user.locale = mailLocale(locale)
+
+	token, err := issueToken(user.id, tokenVerifyEmail, config.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+	return enqueueMail(mailVerifyEmail, user, map[string]interface{}{
+		"Hours": int(config.EmailVerificationTTL.Hours()),
+		"Link":  config.AppBaseURL + "/verify-email?token=" + url.QueryEscape(token),
+	})
+}
+
+// VerifyEmail 使用验证令牌确认邮箱
+func (s *AccountService) VerifyEmail(token string) error {
+	userID, err := consumeToken(token, tokenVerifyEmail)
+	if err != nil {
+		return err
+	}
+	_, err = config.DB.Exec(
+		"UPDATE users SET email_verified_at = COALESCE(email_verified_at, ?) WHERE id = ?",
+		time.Now(), userID,
+	)
+	return err
+}
+
+// ForgotPassword 在后台为该邮箱下的账号发送密码重置邮件。
+// 无论邮箱是否注册都立即返回，避免通过响应内容或耗时判断邮箱是否存在
+func (s *AccountService) ForgotPassword(email string) {
+	Go(func() {
+		if err := s.sendPasswordReset(email); err != nil {
+			s.logger.Error("send password reset email failed", "error", err)
+		}
+	})
+}
+
+// sendPasswordReset 作废之前未使用的重置令牌，为邮箱对应的每个账号发送新的重置链接
+func (s *AccountService) sendPasswordReset(email string) error {
+	rows, err := config.DB.Query("SELECT id, username, email, locale FROM users WHERE email = ?", email)
+	if err != nil {
+		return err
+	}
+	var users []mailRecipient
+	for rows.Next() {
+		var user mailRecipient
+		var locale sql.NullString
+		if err := rows.Scan(&user.id, &user.username, &user.email, &locale); err != nil {
+			rows.Close()
+			return err
+		}
+		user.locale = mailLocale(locale)
+		users = append(users, user)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	minutes := int(config.PasswordResetTTL.Minutes())
+	for _, user := range users {
+		if err := revokeTokens(user.id, tokenResetPassword); err != nil {
+			return err
+		}
+		token, err := issueToken(user.id, tokenResetPassword, config.PasswordResetTTL)
+		if err != nil {
+			return err
+		}
+		err = enqueueMail(mailResetPassword, user, map[string]interface{}{
+			"Minutes": minutes,
+			"Link":    config.AppBaseURL + "/reset-password?token=" + url.QueryEscape(token),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResetPassword 使用重置令牌设置新密码，同时解除登录锁定
+func (s *AccountService) ResetPassword(token, newPassword string) error {
+	userID, err := consumeToken(token, tokenResetPassword)
+	if err != nil {
+		return err
+	}
+	if err := setPassword(userID, newPassword); err != nil {
+		return err
+	}
+	s.notifications.PasswordChanged(userID)
+	return revokeTokens(userID, tokenResetPassword)
+}
+
+// ChangePassword 已登录用户修改密码，需验证原密码
+func (s *AccountService) ChangePassword(userID int, oldPassword, newPassword string) error {
+	var hashedPassword string
+	err := config.DB.QueryRow("SELECT password FROM users WHERE id = ?", userID).Scan(&hashedPassword)
+	if err == sql.ErrNoRows {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !passwordMatches(hashedPassword, oldPassword) {
+		return ErrOldPassword
+	}
+	if err := setPassword(userID, newPassword); err != nil {
+		return err
+	}
+	s.notifications.PasswordChanged(userID)
+	return revokeTokens(userID, tokenResetPassword)
+}
+
+// setPassword 保存新密码的哈希并清除登录失败记录
+func setPassword(userID int, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	_, err = config.DB.Exec(
+		"UPDATE users SET password = ?, failed_logins = 0, locked_until = NULL WHERE id = ?",
+		hashedPassword, userID,
+	)
+	return err
+}
+
+// issueToken 生成一次性令牌，数据库只保存其哈希，返回给用户的明文令牌只出现在邮件中
+func issueToken(userID int, purpose string, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now()
+	_, err := config.DB.Exec(`
+		INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, userID, purpose, hashToken(token), now.Add(ttl), now)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// consumeToken 校验并作废令牌，返回所属用户。令牌不存在、已使用、已过期或用途不符时返回 ErrInvalidUserToken
+func consumeToken(token, purpose string) (int, error) {
+	hash := hashToken(token)
+	now := time.Now()
+
+	// 条件更新保证并发请求中只有一个能使用同一令牌
+	result, err := config.DB.Exec(`
+		UPDATE user_tokens SET used_at = ?
+		WHERE token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?
+	`, now, hash, purpose, now)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if affected == 0 {
+		return 0, ErrInvalidUserToken
+	}
+
+	var userID int
+	err = config.DB.QueryRow("SELECT user_id FROM user_tokens WHERE token_hash = ?", hash).Scan(&userID)
+	return userID, err
+}
+
+// revokeTokens 作废用户某一用途下所有未使用的令牌
+func revokeTokens(userID int, purpose string) error {
+	_, err := config.DB.Exec(`
+		UPDATE user_tokens SET used_at = ?
+		WHERE user_id = ? AND purpose = ? AND used_at IS NULL
+	`, time.Now(), userID, purpose)
+	return err
+}
+
+// hashToken 令牌的SHA-256哈希
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
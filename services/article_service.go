@@ -2,21 +2,37 @@ package services
 
 import (
 	"database/sql"
+	"log/slog"
 	"my_blog/config"
+	"my_blog/events"
+	"my_blog/metrics"
 	"my_blog/models"
 	"time"
 )
 
 // ArticleService 文章服务
-type ArticleService struct{}
+type ArticleService struct {
+	media         *MediaService
+	notifications *NotificationService
+	reactions     *ReactionService
+}
+
+// NewArticleService 创建文章服务
+func NewArticleService(logger *slog.Logger) *ArticleService {
+	return &ArticleService{
+		media:         NewMediaService(logger),
+		notifications: NewNotificationService(logger),
+		reactions:     &ReactionService{},
+	}
+}
 
-// GetAllArticles 获取所有文章
-func (s *ArticleService) GetAllArticles() ([]models.Article, error) {
+// GetAllArticles 获取所有文章，包含反应统计，viewerID 为当前用户（未登录为0）
+func (s *ArticleService) GetAllArticles(viewerID int) ([]models.Article, error) {
 	rows, err := config.DB.Query(`
-		SELECT a.id, a.author, a.title, a.content, a.create_at, a.image_path, a.views,
-			   c.id, c.name, c.description
+		SELECT ` + articleColumns + `
 		FROM articles a
 		LEFT JOIN categories c ON a.category_id = c.id
+		WHERE a.deleted_at IS NULL
 	`)
 	if err != nil {
 		return nil, err
@@ -25,39 +41,64 @@ func (s *ArticleService) GetAllArticles() ([]models.Article, error) {
 
 	var articles []models.Article
 	for rows.Next() {
-		var article models.Article
-		err := rows.Scan(
-			&article.ID,
-			&article.Author,
-			&article.Title,
-			&article.Content,
-			&article.CreateAt,
-			&article.ImagePath,
-			&article.Views,
-			&article.Category.ID,
-			&article.Category.Name,
-			&article.Category.Description,
-		)
+		article, err := scanArticle(rows)
 		if err != nil {
 			return nil, err
 		}
 		articles = append(articles, article)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.reactions.attachToArticles(articles, viewerID); err != nil {
+		return nil, err
+	}
 	return articles, nil
 }
 
-// GetArticleByID 根据ID获取文章
-func (s *ArticleService) GetArticleByID(id int) (*models.Article, error) {
-	var article models.Article
-	err := config.DB.QueryRow(`
-		SELECT a.id, a.author, a.title, a.content, a.create_at, a.image_path, a.views,
-			   c.id, c.name, c.description
+// GetArticleByID 根据ID获取文章，包含反应统计，viewerID 为当前用户（未登录为0）
+func (s *ArticleService) GetArticleByID(id, viewerID int) (*models.Article, error) {
+	article, err := scanArticle(config.DB.QueryRow(`
+		SELECT `+articleColumns+`
 		FROM articles a
 		LEFT JOIN categories c ON a.category_id = c.id
-		WHERE a.id = ?
-	`, id).Scan(
+		WHERE a.id = ? AND a.deleted_at IS NULL
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrArticleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := s.reactions.summaries(articleReactions, []int{article.ID}, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	article.ReactionSummary = summaries[article.ID]
+	return &article, nil
+}
+
+// articleExists 判断文章是否存在且不在回收站中
+func articleExists(id int) (bool, error) {
+	var exists bool
+	err := config.DB.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM articles WHERE id = ? AND deleted_at IS NULL)", id,
+	).Scan(&exists)
+	return exists, err
+}
+
+// articleColumns 文章查询的列，文章表别名为 a、分类表别名为 c，与 scanArticle 的顺序一致
+const articleColumns = "a.id, a.author, a.author_id, a.title, a.content, a.create_at, a.image_path, a.views, c.id, c.name, c.description"
+
+// scanArticle 按 articleColumns 的顺序读取文章并填充配图变体
+func scanArticle(row rowScanner) (models.Article, error) {
+	var article models.Article
+	var authorID sql.NullInt64
+	err := row.Scan(
 		&article.ID,
 		&article.Author,
+		&authorID,
 		&article.Title,
 		&article.Content,
 		&article.CreateAt,
@@ -67,59 +108,142 @@ func (s *ArticleService) GetArticleByID(id int) (*models.Article, error) {
 		&article.Category.Name,
 		&article.Category.Description,
 	)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
 	if err != nil {
-		return nil, err
+		return article, err
+	}
+	article.AuthorID = int(authorID.Int64)
+	setArticleImageVariants(&article)
+	return article, nil
+}
+
+// setArticleImageVariants 填充文章配图的多尺寸变体路径
+func setArticleImageVariants(article *models.Article) {
+	if article.ImagePath != nil {
+		article.ImageVariants = ImageVariantsFor(*article.ImagePath)
 	}
-	return &article, nil
 }
 
-// CreateArticle 创建文章
+// CreateArticle 创建文章，作者为 article.AuthorID 对应的用户，以其当前用户名填充 article.Author
 func (s *ArticleService) CreateArticle(article *models.Article, categoryName string) (int64, error) {
 	var categoryID int
 	err := config.DB.QueryRow("SELECT id FROM categories WHERE name = ?", categoryName).Scan(&categoryID)
+	if err == sql.ErrNoRows {
+		return 0, ErrCategoryNotFound
+	}
 	if err != nil {
 		return 0, err
 	}
+	if article.Author, err = usernameOf(article.AuthorID); err != nil {
+		return 0, err
+	}
 
+	createAt := time.Now()
 	result, err := config.DB.Exec(`
-		INSERT INTO articles (title, content, author, create_at, image_path, category_id, views)
-		VALUES (?, ?, ?, ?, ?, ?, 0)
+		INSERT INTO articles (title, content, author, author_id, create_at, image_path, category_id, views)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 0)
 	`,
 		article.Title,
 		article.Content,
 		article.Author,
-		time.Now(),
+		article.AuthorID,
+		createAt,
 		article.ImagePath,
 		categoryID,
 	)
 	if err != nil {
 		return 0, err
 	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
 
-	return result.LastInsertId()
+	if article.ImagePath != nil {
+		s.media.acquireUpload(*article.ImagePath)
+	}
+	metrics.ArticlesCreated.Inc()
+	emit(events.ArticleCreated, map[string]interface{}{
+		"id":        id,
+		"title":     article.Title,
+		"author":    article.Author,
+		"category":  categoryName,
+		"create_at": createAt,
+	})
+	return id, nil
 }
 
-// UpdateArticle 更新文章
-func (s *ArticleService) UpdateArticle(id int, article *models.Article) error {
-	_, err := config.DB.Exec(`
-		UPDATE articles 
-		SET title = ?, content = ?, image_path = ?
-		WHERE id = ?
-	`,
-		article.Title,
-		article.Content,
-		article.ImagePath,
-		id,
-	)
-	return err
+// UpdateArticle 部分更新文章，只修改补丁中出现的字段。只有文章作者和管理员（admin 为 true）可以修改
+func (s *ArticleService) UpdateArticle(id, actorID int, admin bool, patch *models.ArticlePatch) error {
+	oldImage, err := s.articleImage(id)
+	if err != nil {
+		return err
+	}
+	_, authorID, err := articleTitleAndAuthor(id)
+	if err != nil {
+		return err
+	}
+	if !admin && (authorID == 0 || authorID != actorID) {
+		return ErrPermissionDenied
+	}
+
+	var set updateSet
+	set.addOptional("title", patch.Title)
+	set.addOptional("content", patch.Content)
+	set.addOptional("image_path", patch.ImagePath)
+	if set.empty() {
+		return nil
+	}
+
+	_, err = config.DB.Exec("UPDATE articles SET "+set.clause()+" WHERE id = ? AND deleted_at IS NULL", append(set.args, id)...)
+	if err != nil {
+		return err
+	}
+
+	if patch.ImagePath.Set {
+		s.media.replaceUpload(oldImage, patch.ImagePath.Value)
+	}
+	title, _, err := articleTitleAndAuthor(id)
+	if err != nil {
+		return err
+	}
+	emit(events.ArticleUpdated, map[string]interface{}{
+		"id":    id,
+		"title": title,
+	})
+	return nil
+}
+
+// articleImage 获取文章当前的配图路径，文章在回收站中时视为不存在
+func (s *ArticleService) articleImage(id int) (string, error) {
+	var imagePath sql.NullString
+	err := config.DB.QueryRow("SELECT image_path FROM articles WHERE id = ? AND deleted_at IS NULL", id).Scan(&imagePath)
+	if err == sql.ErrNoRows {
+		return "", ErrArticleNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return imagePath.String, nil
 }
 
-// DeleteArticle 删除文章
-func (s *ArticleService) DeleteArticle(id int) error {
-	result, err := config.DB.Exec("DELETE FROM articles WHERE id = ?", id)
+// DeleteArticle 将文章移入回收站，保留期内可恢复。只有文章作者和管理员（admin 为 true）可以删除，
+// 管理员删除时通知文章作者。配图仍被回收站中的文章引用，永久删除时才释放
+func (s *ArticleService) DeleteArticle(id, actorID int, admin bool) error {
+	title, authorID, err := articleTitleAndAuthor(id)
+	if err == sql.ErrNoRows {
+		return ErrArticleNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !admin && (authorID == 0 || authorID != actorID) {
+		return ErrPermissionDenied
+	}
+
+	result, err := config.DB.Exec(
+		"UPDATE articles SET deleted_at = ?, deleted_by = ? WHERE id = ? AND deleted_at IS NULL",
+		time.Now(), idPtr(actorID), id,
+	)
 	if err != nil {
 		return err
 	}
@@ -130,20 +254,26 @@ func (s *ArticleService) DeleteArticle(id int) error {
 	}
 
 	if affected == 0 {
-		return sql.ErrNoRows
+		return ErrArticleNotFound
 	}
 
+	emit(events.ArticleDeleted, map[string]interface{}{
+		"id":    id,
+		"title": title,
+	})
+	if authorID != 0 && authorID != actorID {
+		s.notifications.ArticleRemoved(authorID, title)
+	}
 	return nil
 }
 
-// GetArticlesByCategory 获取分类下的所有文章
-func (s *ArticleService) GetArticlesByCategory(categoryID int) ([]models.Article, error) {
+// GetArticlesByCategory 获取分类下的所有文章，包含反应统计，viewerID 为当前用户（未登录为0）
+func (s *ArticleService) GetArticlesByCategory(categoryID, viewerID int) ([]models.Article, error) {
 	rows, err := config.DB.Query(`
-		SELECT a.id, a.author, a.title, a.content, a.create_at, a.image_path, a.views,
-			   c.id, c.name, c.description
+		SELECT `+articleColumns+`
 		FROM articles a
 		LEFT JOIN categories c ON a.category_id = c.id
-		WHERE a.category_id = ?
+		WHERE a.category_id = ? AND a.deleted_at IS NULL
 	`, categoryID)
 	if err != nil {
 		return nil, err
@@ -152,23 +282,17 @@ func (s *ArticleService) GetArticlesByCategory(categoryID int) ([]models.Article
 
 	var articles []models.Article
 	for rows.Next() {
-		var article models.Article
-		err := rows.Scan(
-			&article.ID,
-			&article.Author,
-			&article.Title,
-			&article.Content,
-			&article.CreateAt,
-			&article.ImagePath,
-			&article.Views,
-			&article.Category.ID,
-			&article.Category.Name,
-			&article.Category.Description,
-		)
+		article, err := scanArticle(rows)
 		if err != nil {
 			return nil, err
 		}
 		articles = append(articles, article)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.reactions.attachToArticles(articles, viewerID); err != nil {
+		return nil, err
+	}
 	return articles, nil
 }
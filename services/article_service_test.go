@@ -0,0 +1,79 @@
+package services
+
+import (
+	"my_blog/models"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCreateArticleUsesAuthorAccount(t *testing.T) {
+	mock := useMockDB(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM categories WHERE name = ?")).WithArgs("Go").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT username FROM users WHERE id = ?")).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"username"}).AddRow("alice"))
+	mock.ExpectExec("INSERT INTO articles").
+		WithArgs("标题", "内容", "alice", 7, sqlmock.AnyArg(), nil, 4).
+		WillReturnResult(sqlmock.NewResult(11, 1))
+
+	// 请求中伪造的作者名会被账号的用户名覆盖
+	article := &models.Article{Title: "标题", Content: "内容", Author: "bob", AuthorID: 7}
+	id, err := (&ArticleService{}).CreateArticle(article, "Go")
+	if err != nil || id != 11 {
+		t.Fatalf("CreateArticle = %d, %v", id, err)
+	}
+	if article.Author != "alice" {
+		t.Errorf("Author = %q, want alice", article.Author)
+	}
+}
+
+func TestCreateArticleUnknownAuthor(t *testing.T) {
+	mock := useMockDB(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM categories WHERE name = ?")).WithArgs("Go").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT username FROM users WHERE id = ?")).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"username"}))
+
+	if _, err := (&ArticleService{}).CreateArticle(&models.Article{AuthorID: 7}, "Go"); err != ErrUserNotFound {
+		t.Errorf("CreateArticle = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestUpdateArticleRequiresAuthorOrAdmin(t *testing.T) {
+	tests := []struct {
+		name     string
+		actorID  int
+		admin    bool
+		authorID interface{}
+		want     error
+	}{
+		{"author", 2, false, 2, nil},
+		{"admin", 1, true, 2, nil},
+		{"other user", 3, false, 2, ErrPermissionDenied},
+		{"article without account", 3, false, nil, ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := useMockDB(t)
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT image_path FROM articles WHERE id = ? AND deleted_at IS NULL")).WithArgs(10).
+				WillReturnRows(sqlmock.NewRows([]string{"image_path"}).AddRow(nil))
+			expectTitle := func() {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT title, author_id FROM articles WHERE id = ?")).WithArgs(10).
+					WillReturnRows(sqlmock.NewRows([]string{"title", "author_id"}).AddRow("标题", tt.authorID))
+			}
+			expectTitle()
+			if tt.want == nil {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE articles SET title = ? WHERE id = ? AND deleted_at IS NULL")).
+					WithArgs("新标题", 10).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectTitle()
+			}
+
+			patch := &models.ArticlePatch{Title: models.Some("新标题")}
+			if err := (&ArticleService{}).UpdateArticle(10, tt.actorID, tt.admin, patch); err != tt.want {
+				t.Errorf("UpdateArticle = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
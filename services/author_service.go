@@ -0,0 +1,164 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"my_blog/config"
+	"my_blog/models"
+)
+
+// authorRecentArticles 作者主页附带的最近文章数量，更多文章通过分页接口获取
+const authorRecentArticles = 5
+
+// profileColumns users 表中个人资料的列，按 Bio、Website、Location、社交链接JSON 的顺序读取
+const profileColumns = "COALESCE(bio, ''), COALESCE(website, ''), COALESCE(location, ''), COALESCE(social_links, '')"
+
+// AuthorService 作者公开主页
+type AuthorService struct {
+	reactions *ReactionService
+}
+
+// NewAuthorService 创建作者服务
+func NewAuthorService() *AuthorService {
+	return &AuthorService{reactions: &ReactionService{}}
+}
+
+// GetAuthorProfile 根据用户名获取作者主页，包含统计数据和最近的文章，viewerID 为当前用户（未登录为0）
+func (s *AuthorService) GetAuthorProfile(username string, viewerID int) (*models.AuthorProfile, error) {
+	var author models.AuthorProfile
+	var links string
+	err := config.DB.QueryRow(`
+		SELECT id, username, COALESCE(image_data, ''), COALESCE(background_image, ''), `+profileColumns+`
+		FROM users WHERE username = ? AND status <> ?
+	`, username, models.UserStatusDeleted).Scan(
+		&author.ID,
+		&author.Username,
+		&author.ImageData,
+		&author.BackgroundImage,
+		&author.Bio,
+		&author.Website,
+		&author.Location,
+		&links,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if author.SocialLinks, err = decodeSocialLinks(links); err != nil {
+		return nil, err
+	}
+
+	if author.Stats, err = authorStats(author.ID); err != nil {
+		return nil, err
+	}
+
+	if viewerID != 0 && viewerID != author.ID {
+		err = config.DB.QueryRow(
+			"SELECT EXISTS(SELECT 1 FROM user_follows WHERE follower_id = ? AND followee_id = ?)", viewerID, author.ID,
+		).Scan(&author.FollowedByMe)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	articles, err := s.articles(author.ID, viewerID, authorRecentArticles, 0)
+	if err != nil {
+		return nil, err
+	}
+	author.Articles = articles
+
+	author.ImageVariants = ImageVariantsFor(author.ImageData)
+	author.BackgroundVariants = ImageVariantsFor(author.BackgroundImage)
+	return &author, nil
+}
+
+// GetAuthorArticles 分页获取作者发布的文章，按发布时间倒序
+func (s *AuthorService) GetAuthorArticles(username string, viewerID, limit, offset int) (*models.ArticleList, error) {
+	list := &models.ArticleList{}
+	var authorID int
+	err := config.DB.QueryRow(`
+		SELECT u.id, COUNT(a.id)
+		FROM users u
+		LEFT JOIN articles a ON a.author_id = u.id AND a.deleted_at IS NULL
+		WHERE u.username = ? AND u.status <> ?
+		GROUP BY u.id
+	`, username, models.UserStatusDeleted).Scan(&authorID, &list.Total)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if list.Items, err = s.articles(authorID, viewerID, limit, offset); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// articles 按发布时间倒序获取作者的文章
+func (s *AuthorService) articles(authorID, viewerID, limit, offset int) ([]models.Article, error) {
+	rows, err := config.DB.Query(`
+		SELECT `+articleColumns+`
+		FROM articles a
+		LEFT JOIN categories c ON a.category_id = c.id
+		WHERE a.author_id = ? AND a.deleted_at IS NULL
+		ORDER BY a.create_at DESC, a.id DESC
+		LIMIT ? OFFSET ?
+	`, authorID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	articles := []models.Article{}
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, article)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.reactions.attachToArticles(articles, viewerID); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// authorStats 统计作者的文章、阅读量、评论、收到的反应及关注数据，不含回收站中的内容
+func authorStats(userID int) (models.AuthorStats, error) {
+	var stats models.AuthorStats
+	err := config.DB.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM articles WHERE author_id = ? AND deleted_at IS NULL),
+			(SELECT COALESCE(SUM(views), 0) FROM articles WHERE author_id = ? AND deleted_at IS NULL),
+			(SELECT COUNT(*)`+visibleComments+` AND c.user_id = ?),
+			(SELECT COUNT(*) FROM article_reactions r JOIN articles a ON a.id = r.article_id WHERE a.author_id = ? AND a.deleted_at IS NULL)
+	`, userID, userID, userID, userID).Scan(
+		&stats.Articles,
+		&stats.Views,
+		&stats.Comments,
+		&stats.ReactionsReceived,
+	)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.Followers, stats.Following, err = followCounts(userID)
+	return stats, err
+}
+
+// decodeSocialLinks 解析以JSON保存的社交链接，未填写时为空
+func decodeSocialLinks(raw string) (models.SocialLinks, error) {
+	var links models.SocialLinks
+	if raw == "" {
+		return links, nil
+	}
+	err := json.Unmarshal([]byte(raw), &links)
+	return links, err
+}
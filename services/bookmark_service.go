@@ -0,0 +1,85 @@
+package services
+
+import (
+	"my_blog/config"
+	"my_blog/models"
+	"time"
+)
+
+// BookmarkService 用户收藏的文章
+type BookmarkService struct {
+	reactions *ReactionService
+}
+
+// NewBookmarkService 创建收藏服务
+func NewBookmarkService() *BookmarkService {
+	return &BookmarkService{reactions: &ReactionService{}}
+}
+
+// AddBookmark 收藏文章，重复收藏不报错
+func (s *BookmarkService) AddBookmark(userID, articleID int) error {
+	exists, err := articleExists(articleID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrArticleNotFound
+	}
+
+	_, err = config.DB.Exec(
+		"INSERT INTO bookmarks (user_id, article_id, created_at) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE created_at = created_at",
+		userID, articleID, time.Now(),
+	)
+	if isMissingReference(err) {
+		return ErrArticleNotFound
+	}
+	return err
+}
+
+// RemoveBookmark 取消收藏，未收藏时不报错
+func (s *BookmarkService) RemoveBookmark(userID, articleID int) error {
+	_, err := config.DB.Exec("DELETE FROM bookmarks WHERE user_id = ? AND article_id = ?", userID, articleID)
+	return err
+}
+
+// GetBookmarks 分页获取用户收藏的文章，按收藏时间倒序，回收站中的文章不返回
+func (s *BookmarkService) GetBookmarks(userID, limit, offset int) (*models.ArticleList, error) {
+	list := &models.ArticleList{Items: []models.Article{}}
+	err := config.DB.QueryRow(`
+		SELECT COUNT(*) FROM bookmarks b
+		JOIN articles a ON a.id = b.article_id
+		WHERE b.user_id = ? AND a.deleted_at IS NULL
+	`, userID).Scan(&list.Total)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT `+articleColumns+`
+		FROM bookmarks b
+		JOIN articles a ON a.id = b.article_id
+		LEFT JOIN categories c ON a.category_id = c.id
+		WHERE b.user_id = ? AND a.deleted_at IS NULL
+		ORDER BY b.created_at DESC, b.article_id DESC
+		LIMIT ? OFFSET ?
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, article)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.reactions.attachToArticles(list.Items, userID); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -47,7 +47,7 @@ func (s *CategoryService) GetCategoryByID(id int) (*models.Category, error) {
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, ErrCategoryNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -65,6 +65,9 @@ func (s *CategoryService) CreateCategory(category *models.Category) (int64, erro
 		category.Name,
 		category.Description,
 	)
+	if isDuplicateEntry(err) {
+		return 0, ErrCategoryExists
+	}
 	if err != nil {
 		return 0, err
 	}
@@ -83,6 +86,9 @@ func (s *CategoryService) UpdateCategory(id int, category *models.Category) erro
 		category.Description,
 		id,
 	)
+	if isDuplicateEntry(err) {
+		return ErrCategoryExists
+	}
 	if err != nil {
 		return err
 	}
@@ -92,8 +98,11 @@ func (s *CategoryService) UpdateCategory(id int, category *models.Category) erro
 		return err
 	}
 
+	// 数据未变化时影响行数也为0，需确认分类是否存在
 	if affected == 0 {
-		return sql.ErrNoRows
+		if _, err := s.GetCategoryByID(id); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -112,7 +121,7 @@ func (s *CategoryService) DeleteCategory(id int) error {
 	}
 
 	if affected == 0 {
-		return sql.ErrNoRows
+		return ErrCategoryNotFound
 	}
 
 	return nil
@@ -2,21 +2,80 @@ package services
 
 import (
 	"database/sql"
+	"log/slog"
 	"my_blog/config"
+	"my_blog/events"
+	"my_blog/metrics"
 	"my_blog/models"
 	"time"
 )
 
 // CommentService 评论服务
-type CommentService struct{}
+type CommentService struct {
+	notifications *NotificationService
+	reactions     *ReactionService
+}
+
+// NewCommentService 创建评论服务，新评论、回复和移除评论时发送通知
+func NewCommentService(logger *slog.Logger) *CommentService {
+	return &CommentService{
+		notifications: NewNotificationService(logger),
+		reactions:     &ReactionService{},
+	}
+}
+
+// rowScanner 兼容 *sql.Row 和 *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// commentColumns 评论查询的列，评论表别名为 c，与 scanComment 的顺序一致
+const commentColumns = "c.id, c.article_id, c.content, c.author, c.create_at, c.updated_at, c.user_id, c.parent_id"
+
+// visibleComments 评论查询的表及可见条件：评论、所属文章和被回复的评论都不在回收站中，
+// 文章表别名为 a，被回复的评论别名为 p
+const visibleComments = `
+	FROM comments c
+	JOIN articles a ON a.id = c.article_id
+	LEFT JOIN comments p ON p.id = c.parent_id
+	WHERE c.deleted_at IS NULL AND a.deleted_at IS NULL AND p.deleted_at IS NULL`
+
+// commentExists 判断评论是否存在且可见
+func commentExists(id int) (bool, error) {
+	var exists bool
+	err := config.DB.QueryRow("SELECT EXISTS(SELECT 1"+visibleComments+" AND c.id = ?)", id).Scan(&exists)
+	return exists, err
+}
+
+// scanComment 按 commentColumns 的顺序读取评论
+func scanComment(row rowScanner) (models.Comment, error) {
+	var comment models.Comment
+	var updatedAt sql.NullTime
+	var userID, parentID sql.NullInt64
+	err := row.Scan(
+		&comment.ID,
+		&comment.ArticleID,
+		&comment.Content,
+		&comment.Author,
+		&comment.CreateAt,
+		&updatedAt,
+		&userID,
+		&parentID,
+	)
+	comment.UpdatedAt = nullTime(updatedAt)
+	comment.UserID = int(userID.Int64)
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		comment.ParentID = &id
+	}
+	return comment, err
+}
 
-// GetCommentsByArticle 获取文章的所有评论
-func (s *CommentService) GetCommentsByArticle(articleID int) ([]models.Comment, error) {
+// GetCommentsByArticle 获取文章的所有评论，包含反应统计，viewerID 为当前用户（未登录为0）
+func (s *CommentService) GetCommentsByArticle(articleID, viewerID int) ([]models.Comment, error) {
 	rows, err := config.DB.Query(`
-		SELECT id, article_id, content, author, create_at
-		FROM comments
-		WHERE article_id = ?
-		ORDER BY create_at DESC
+		SELECT `+commentColumns+visibleComments+` AND c.article_id = ?
+		ORDER BY c.create_at DESC
 	`, articleID)
 	if err != nil {
 		return nil, err
@@ -25,57 +84,118 @@ func (s *CommentService) GetCommentsByArticle(articleID int) ([]models.Comment,
 
 	var comments []models.Comment
 	for rows.Next() {
-		var comment models.Comment
-		err := rows.Scan(
-			&comment.ID,
-			&comment.ArticleID,
-			&comment.Content,
-			&comment.Author,
-			&comment.CreateAt,
-		)
+		comment, err := scanComment(rows)
 		if err != nil {
 			return nil, err
 		}
 		comments = append(comments, comment)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	comments = withoutHiddenReplies(comments)
+	if err := s.reactions.attachToComments(comments, viewerID); err != nil {
+		return nil, err
+	}
 	return comments, nil
 }
 
-// CreateComment 创建评论
+// withoutHiddenReplies 去掉回复链上有评论不可见的回复。visibleComments 只排除直接回复回收站中评论的回复，
+// 更深层的回复在这里按被回复的评论是否在结果中排除；comments 须包含文章的全部可见评论
+func withoutHiddenReplies(comments []models.Comment) []models.Comment {
+	loaded := make(map[int]*models.Comment, len(comments))
+	for i := range comments {
+		loaded[comments[i].ID] = &comments[i]
+	}
+
+	shown := make(map[int]bool, len(comments))
+	var isShown func(c *models.Comment) bool
+	isShown = func(c *models.Comment) bool {
+		if c.ParentID == nil {
+			return true
+		}
+		if v, ok := shown[c.ID]; ok {
+			return v
+		}
+		shown[c.ID] = false // 防止异常数据中的循环引用
+		parent, ok := loaded[*c.ParentID]
+		v := ok && isShown(parent)
+		shown[c.ID] = v
+		return v
+	}
+
+	visible := make([]models.Comment, 0, len(comments))
+	for i := range comments {
+		if isShown(&comments[i]) {
+			visible = append(visible, comments[i])
+		}
+	}
+	return visible
+}
+
+// CreateComment 创建评论，ParentID 不为空时为回复，被回复的评论须属于同一篇文章。
+// 作者为 comment.UserID 对应的用户，以其当前用户名填充 comment.Author
 func (s *CommentService) CreateComment(comment *models.Comment) (int64, error) {
+	if comment.ParentID != nil {
+		parent, err := s.GetCommentByID(*comment.ParentID)
+		if err == ErrCommentNotFound || (err == nil && parent.ArticleID != comment.ArticleID) {
+			return 0, ErrInvalidParentComment
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	exists, err := articleExists(comment.ArticleID)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, ErrArticleNotFound
+	}
+	if comment.Author, err = usernameOf(comment.UserID); err != nil {
+		return 0, err
+	}
+
+	comment.CreateAt = time.Now()
 	result, err := config.DB.Exec(`
-		INSERT INTO comments (article_id, content, author, create_at)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO comments (article_id, content, author, create_at, user_id, parent_id)
+		VALUES (?, ?, ?, ?, ?, ?)
 	`,
 		comment.ArticleID,
 		comment.Content,
 		comment.Author,
-		time.Now(),
+		comment.CreateAt,
+		comment.UserID,
+		comment.ParentID,
 	)
+	if isMissingReference(err) {
+		return 0, ErrArticleNotFound
+	}
 	if err != nil {
 		return 0, err
 	}
 
-	return result.LastInsertId()
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	comment.ID = int(id)
+
+	metrics.CommentsCreated.Inc()
+	emit(events.CommentCreated, comment)
+	s.notifications.CommentCreated(comment)
+	return id, nil
 }
 
-// GetCommentByID 根据ID获取评论
+// GetCommentByID 根据ID获取评论，评论或所属文章在回收站中时视为不存在
 func (s *CommentService) GetCommentByID(id int) (*models.Comment, error) {
-	var comment models.Comment
-	err := config.DB.QueryRow(`
-		SELECT id, article_id, content, author, create_at
-		FROM comments
-		WHERE id = ?
-	`, id).Scan(
-		&comment.ID,
-		&comment.ArticleID,
-		&comment.Content,
-		&comment.Author,
-		&comment.CreateAt,
-	)
+	comment, err := scanComment(config.DB.QueryRow(`
+		SELECT `+commentColumns+visibleComments+` AND c.id = ?
+	`, id))
 
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, ErrCommentNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -84,12 +204,20 @@ func (s *CommentService) GetCommentByID(id int) (*models.Comment, error) {
 	return &comment, nil
 }
 
-// UpdateComment 更新评论
-func (s *CommentService) UpdateComment(id int, content string) error {
+// UpdateComment 修改评论内容并记录编辑时间，发表时间不变。只有评论作者和管理员（admin 为 true）可以修改
+func (s *CommentService) UpdateComment(id, actorID int, admin bool, content string) error {
+	comment, err := s.GetCommentByID(id)
+	if err != nil {
+		return err
+	}
+	if !admin && (comment.UserID == 0 || comment.UserID != actorID) {
+		return ErrPermissionDenied
+	}
+
 	result, err := config.DB.Exec(`
 		UPDATE comments
-		SET content = ?, create_at = ?
-		WHERE id = ?
+		SET content = ?, updated_at = ?
+		WHERE id = ? AND deleted_at IS NULL
 	`,
 		content,
 		time.Now(),
@@ -105,15 +233,27 @@ func (s *CommentService) UpdateComment(id int, content string) error {
 	}
 
 	if affected == 0 {
-		return sql.ErrNoRows
+		return ErrCommentNotFound
 	}
 
 	return nil
 }
 
-// DeleteComment 删除评论
-func (s *CommentService) DeleteComment(id int) error {
-	result, err := config.DB.Exec("DELETE FROM comments WHERE id = ?", id)
+// DeleteComment 将评论移入回收站，保留期内可恢复。只有评论作者和管理员（admin 为 true）可以删除，
+// 管理员删除时通知评论作者
+func (s *CommentService) DeleteComment(id, actorID int, admin bool) error {
+	comment, err := s.GetCommentByID(id)
+	if err != nil {
+		return err
+	}
+	if !admin && (comment.UserID == 0 || comment.UserID != actorID) {
+		return ErrPermissionDenied
+	}
+
+	result, err := config.DB.Exec(
+		"UPDATE comments SET deleted_at = ?, deleted_by = ? WHERE id = ? AND deleted_at IS NULL",
+		time.Now(), idPtr(actorID), id,
+	)
 	if err != nil {
 		return err
 	}
@@ -124,8 +264,12 @@ func (s *CommentService) DeleteComment(id int) error {
 	}
 
 	if affected == 0 {
-		return sql.ErrNoRows
+		return ErrCommentNotFound
 	}
 
+	emit(events.CommentDeleted, comment)
+	if comment.UserID != 0 && comment.UserID != actorID {
+		s.notifications.CommentRemoved(comment)
+	}
 	return nil
 }
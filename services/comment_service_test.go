@@ -0,0 +1,102 @@
+package services
+
+import (
+	"io"
+	"log/slog"
+	"my_blog/models"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestWithoutHiddenReplies(t *testing.T) {
+	parent := func(id int) *int { return &id }
+	comment := func(id int, parentID *int) models.Comment {
+		return models.Comment{ID: id, ParentID: parentID}
+	}
+
+	tests := []struct {
+		name     string
+		comments []models.Comment
+		want     []int
+	}{
+		{"empty", nil, []int{}},
+		{"top level only", []models.Comment{comment(1, nil), comment(2, nil)}, []int{1, 2}},
+		{"visible thread", []models.Comment{comment(3, parent(2)), comment(2, parent(1)), comment(1, nil)}, []int{3, 2, 1}},
+		// 2 回复了回收站中的评论，已被 visibleComments 排除，3 随之隐藏
+		{"reply to hidden reply", []models.Comment{comment(3, parent(2)), comment(1, nil)}, []int{1}},
+		{"reply to trashed comment", []models.Comment{comment(4, parent(9)), comment(5, parent(4)), comment(1, nil)}, []int{1}},
+		{"cycle", []models.Comment{comment(1, parent(2)), comment(2, parent(1)), comment(3, nil)}, []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []int{}
+			for _, c := range withoutHiddenReplies(tt.comments) {
+				got = append(got, c.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("visible = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateCommentUsesAuthorAccount(t *testing.T) {
+	mock := useMockDB(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM articles WHERE id = ? AND deleted_at IS NULL)")).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT username FROM users WHERE id = ?")).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"username"}).AddRow("alice"))
+	mock.ExpectExec("INSERT INTO comments").
+		WithArgs(3, "内容", "alice", sqlmock.AnyArg(), 7, nil).
+		WillReturnResult(sqlmock.NewResult(21, 1))
+	// 评论者就是文章作者，不发送通知
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT title, author_id FROM articles WHERE id = ?")).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "author_id"}).AddRow("标题", 7))
+
+	s := NewCommentService(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	comment := &models.Comment{ArticleID: 3, Content: "内容", Author: "bob", UserID: 7}
+	if id, err := s.CreateComment(comment); err != nil || id != 21 {
+		t.Fatalf("CreateComment = %d, %v", id, err)
+	}
+	if comment.Author != "alice" {
+		t.Errorf("Author = %q, want alice", comment.Author)
+	}
+}
+
+func TestUpdateCommentRequiresAuthorOrAdmin(t *testing.T) {
+	const update = "UPDATE comments SET content = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL"
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		actorID int
+		admin   bool
+		userID  interface{}
+		want    error
+	}{
+		{"author", 2, false, 2, nil},
+		{"admin", 1, true, 2, nil},
+		{"other user", 3, false, 2, ErrPermissionDenied},
+		{"anonymous comment", 3, false, nil, ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := useMockDB(t)
+			mock.ExpectQuery("SELECT c.id, (.+) AND c.id = \\?").WithArgs(5).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "content", "author", "create_at", "updated_at", "user_id", "parent_id"}).
+					AddRow(5, 3, "旧内容", "alice", created, nil, tt.userID, nil))
+			if tt.want == nil {
+				// 只记录编辑时间，不修改发表时间
+				mock.ExpectExec(regexp.QuoteMeta(update)).WithArgs("新内容", sqlmock.AnyArg(), 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			if err := (&CommentService{}).UpdateComment(5, tt.actorID, tt.admin, "新内容"); err != tt.want {
+				t.Errorf("UpdateComment = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
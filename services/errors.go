@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"my_blog/apperrors"
+	"my_blog/i18n"
+	"my_blog/models"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// 服务层返回的领域错误
+var (
+	ErrArticleNotFound       = apperrors.NotFound(i18n.MsgArticleNotFound)
+	ErrCategoryNotFound      = apperrors.NotFound(i18n.MsgCategoryNotFound)
+	ErrCommentNotFound       = apperrors.NotFound(i18n.MsgCommentNotFound)
+	ErrUserNotFound          = apperrors.NotFound(i18n.MsgUserNotFound)
+	ErrNotificationNotFound  = apperrors.NotFound(i18n.MsgNotificationNotFound)
+	ErrWebhookNotFound       = apperrors.NotFound(i18n.MsgWebhookNotFound)
+	ErrDeliveryNotFound      = apperrors.NotFound(i18n.MsgDeliveryNotFound)
+	ErrReadingListNotFound   = apperrors.NotFound(i18n.MsgReadingListNotFound)
+	ErrCategoryExists        = apperrors.Conflict(i18n.MsgCategoryExists)
+	ErrUsernameTaken         = apperrors.Conflict(i18n.MsgUsernameTaken)
+	ErrReadingListExists     = apperrors.Conflict(i18n.MsgReadingListExists)
+	ErrPermissionDenied      = apperrors.Forbidden(i18n.MsgPermissionDenied)
+	ErrAccountLocked         = apperrors.Forbidden(i18n.MsgAccountLocked)
+	ErrRestoreNotAllowed     = apperrors.Forbidden(i18n.MsgRestoreNotAllowed)
+	ErrAccountSuspended      = apperrors.Forbidden(i18n.MsgAccountSuspended)
+	ErrAccountBanned         = apperrors.Forbidden(i18n.MsgAccountBanned)
+	ErrInvalidCredentials    = apperrors.Unauthorized(i18n.MsgInvalidCredentials)
+	ErrInvalidUserToken      = apperrors.Validation(i18n.MsgInvalidOrExpiredToken)
+	ErrEmailVerified         = apperrors.Conflict(i18n.MsgEmailAlreadyVerified)
+	ErrInvalidParentComment  = apperrors.Validation(i18n.MsgValidationFailed, models.FieldError{Field: "parent_id", Code: i18n.MsgParentCommentInvalid})
+	ErrOldPassword           = apperrors.Validation(i18n.MsgValidationFailed, models.FieldError{Field: "old_password", Code: i18n.MsgOldPasswordIncorrect})
+	ErrUnknownWebhookEvent   = apperrors.Validation(i18n.MsgValidationFailed, models.FieldError{Field: "events", Code: i18n.MsgUnknownWebhookEvent})
+	ErrCannotFollowSelf      = apperrors.Validation(i18n.MsgCannotFollowSelf)
+	ErrCannotChangeOwnStatus = apperrors.Validation(i18n.MsgCannotChangeOwnStatus)
+	ErrInvalidStatusUntil    = apperrors.Validation(i18n.MsgValidationFailed, models.FieldError{Field: "until", Code: i18n.MsgStatusUntilInvalid})
+	ErrInvalidCursor         = apperrors.Validation(i18n.MsgInvalidCursor)
+	ErrReadingListOrder      = apperrors.Validation(i18n.MsgValidationFailed, models.FieldError{Field: "article_ids", Code: i18n.MsgReadingListOrderInvalid})
+	ErrInvalidReaction       = apperrors.Validation(i18n.MsgValidationFailed, models.FieldError{
+		Field:  "reaction",
+		Code:   i18n.MsgFieldOneOf,
+		Params: []interface{}{strings.Join(ReactionTypes, ", ")},
+	})
+)
+
+// MySQL 错误码
+const (
+	mysqlDuplicateEntry  = 1062
+	mysqlNoReferencedRow = 1452
+)
+
+// isDuplicateEntry 判断是否为唯一键冲突
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+}
+
+// isMissingReference 判断是否为外键引用的记录不存在
+func isMissingReference(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlNoReferencedRow
+}
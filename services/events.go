@@ -0,0 +1,45 @@
+package services
+
+import (
+	"log/slog"
+	"my_blog/config"
+	"my_blog/events"
+	"my_blog/models"
+	"my_blog/realtime"
+)
+
+// EventNotification 推送给用户的站内通知，不属于领域事件，直接发送到用户的实时推送主题
+const EventNotification = "notification"
+
+// RegisterEventHandlers 订阅领域事件：转发给实时推送的订阅者，并写入 Webhook 投递队列
+func RegisterEventHandlers(logger *slog.Logger) {
+	config.Events.Subscribe(forwardToRealtime)
+	config.Events.Subscribe(NewWebhookService(logger).Enqueue)
+}
+
+// emit 发布领域事件
+func emit(eventType string, data interface{}) {
+	config.Events.Publish(eventType, data)
+}
+
+// publish 向实时推送订阅者发送事件
+func publish(topic, eventType string, data interface{}) {
+	config.Realtime.Publish(topic, realtime.Event{Type: eventType, Data: data})
+}
+
+// forwardToRealtime 将读者关心的领域事件推送到对应主题：新文章公告和文章的评论流
+func forwardToRealtime(e events.Event) {
+	switch e.Type {
+	case events.ArticleCreated:
+		publish(realtime.ArticlesTopic, e.Type, e.Data)
+	case events.CommentCreated:
+		comment := e.Data.(*models.Comment)
+		publish(realtime.CommentsTopic(comment.ArticleID), e.Type, comment)
+	case events.CommentDeleted:
+		comment := e.Data.(*models.Comment)
+		publish(realtime.CommentsTopic(comment.ArticleID), e.Type, map[string]interface{}{
+			"id":         comment.ID,
+			"article_id": comment.ArticleID,
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"encoding/base64"
+	"fmt"
+	"my_blog/config"
+	"my_blog/models"
+	"time"
+)
+
+// FollowService 关注用户和分类，以及由关注内容组成的个人动态
+type FollowService struct {
+	reactions *ReactionService
+}
+
+// NewFollowService 创建关注服务
+func NewFollowService() *FollowService {
+	return &FollowService{reactions: &ReactionService{}}
+}
+
+// FollowUser 关注用户，重复关注不报错
+func (s *FollowService) FollowUser(followerID, followeeID int) error {
+	if followerID == followeeID {
+		return ErrCannotFollowSelf
+	}
+	_, err := config.DB.Exec(`
+		INSERT INTO user_follows (follower_id, followee_id, created_at) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE created_at = created_at
+	`, followerID, followeeID, time.Now())
+	if isMissingReference(err) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// UnfollowUser 取消关注用户，未关注时不报错
+func (s *FollowService) UnfollowUser(followerID, followeeID int) error {
+	_, err := config.DB.Exec("DELETE FROM user_follows WHERE follower_id = ? AND followee_id = ?", followerID, followeeID)
+	return err
+}
+
+// FollowCategory 关注分类，重复关注不报错
+func (s *FollowService) FollowCategory(userID, categoryID int) error {
+	_, err := config.DB.Exec(`
+		INSERT INTO category_follows (user_id, category_id, created_at) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE created_at = created_at
+	`, userID, categoryID, time.Now())
+	if isMissingReference(err) {
+		return ErrCategoryNotFound
+	}
+	return err
+}
+
+// UnfollowCategory 取消关注分类，未关注时不报错
+func (s *FollowService) UnfollowCategory(userID, categoryID int) error {
+	_, err := config.DB.Exec("DELETE FROM category_follows WHERE user_id = ? AND category_id = ?", userID, categoryID)
+	return err
+}
+
+// followCounts 统计关注该用户的人数和该用户关注的人数
+func followCounts(userID int) (followers, following int, err error) {
+	err = config.DB.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM user_follows WHERE followee_id = ?),
+			(SELECT COUNT(*) FROM user_follows WHERE follower_id = ?)
+	`, userID, userID).Scan(&followers, &following)
+	return followers, following, err
+}
+
+// Feed 获取关注的作者和分类下的文章，按发布时间倒序。cursor 为上一页返回的 NextCursor，第一页为空
+func (s *FollowService) Feed(userID int, cursor string, limit int) (*models.ArticleFeed, error) {
+	// 游标之前没有文章时，从最新的文章开始
+	before := feedCursor{createAt: time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if cursor != "" {
+		var err error
+		if before, err = decodeFeedCursor(cursor); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT `+articleColumns+`
+		FROM articles a
+		LEFT JOIN categories c ON a.category_id = c.id
+		WHERE (
+			a.author_id IN (SELECT followee_id FROM user_follows WHERE follower_id = ?)
+			OR a.category_id IN (SELECT category_id FROM category_follows WHERE user_id = ?)
+		)
+		AND a.deleted_at IS NULL
+		AND (a.create_at < ? OR (a.create_at = ? AND a.id < ?))
+		ORDER BY a.create_at DESC, a.id DESC
+		LIMIT ?
+	`, userID, userID, before.createAt, before.createAt, before.id, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	feed := &models.ArticleFeed{Items: []models.Article{}}
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		feed.Items = append(feed.Items, article)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 多取的一条用于判断是否还有下一页
+	if len(feed.Items) > limit {
+		feed.Items = feed.Items[:limit]
+		last := feed.Items[limit-1]
+		feed.NextCursor = feedCursor{createAt: last.CreateAt, id: last.ID}.encode()
+	}
+	if err := s.reactions.attachToArticles(feed.Items, userID); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// feedCursor 动态的分页位置：上一页最后一篇文章的发布时间和ID
+type feedCursor struct {
+	createAt time.Time
+	id       int
+}
+
+// encode 编码为不透明的游标字符串
+func (c feedCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.createAt.Unix(), c.id)))
+}
+
+// decodeFeedCursor 解析 encode 生成的游标
+func decodeFeedCursor(cursor string) (feedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return feedCursor{}, err
+	}
+	var unix int64
+	var id int
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &unix, &id); err != nil {
+		return feedCursor{}, err
+	}
+	return feedCursor{createAt: time.Unix(unix, 0), id: id}, nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestFeedCursorRoundTrip(t *testing.T) {
+	tests := []feedCursor{
+		{createAt: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC), id: 42},
+		{createAt: time.Unix(0, 0), id: 0},
+		{createAt: time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC), id: 1 << 40},
+	}
+	for _, want := range tests {
+		encoded := want.encode()
+		got, err := decodeFeedCursor(encoded)
+		if err != nil {
+			t.Fatalf("decodeFeedCursor(%q): %v", encoded, err)
+		}
+		if !got.createAt.Equal(want.createAt) || got.id != want.id {
+			t.Errorf("round trip = %v/%d, want %v/%d", got.createAt, got.id, want.createAt, want.id)
+		}
+	}
+}
+
+func TestFeedCursorTruncatesToSeconds(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 999_000_000, time.UTC)
+	got, err := decodeFeedCursor(feedCursor{createAt: at, id: 1}.encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := at.Truncate(time.Second); !got.createAt.Equal(want) {
+		t.Errorf("createAt = %v, want %v", got.createAt, want)
+	}
+}
+
+func TestDecodeFeedCursorInvalid(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte("1700000000:55"))},
+		{"empty payload", encode("")},
+		{"missing id", encode("1700000000")},
+		{"missing separator", encode("1700000000 5")},
+		{"non numeric time", encode("yesterday:5")},
+		{"non numeric id", encode("1700000000:abc")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := decodeFeedCursor(tt.cursor); err == nil {
+				t.Errorf("decodeFeedCursor(%q) = %+v, want error", tt.cursor, got)
+			}
+		})
+	}
+}
+
+func TestFeedRejectsInvalidCursor(t *testing.T) {
+	useMockDB(t)
+	if _, err := (&FollowService{}).Feed(1, "not a cursor", 10); err != ErrInvalidCursor {
+		t.Errorf("Feed = %v, want ErrInvalidCursor", err)
+	}
+}
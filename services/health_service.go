@@ -0,0 +1,21 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_blog/config"
+)
+
+// ErrShuttingDown 服务正在关闭，不再接收新请求
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// HealthService 健康检查服务
+type HealthService struct{}
+
+// Ready 检查服务是否可以处理请求：未进入关闭流程且数据库可用
+func (s *HealthService) Ready(ctx context.Context) error {
+	if shuttingDown.Load() {
+		return ErrShuttingDown
+	}
+	return config.DB.PingContext(ctx)
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"image"
+	"image/draw"
+	_ "image/gif" // 注册GIF解码器
+	"image/jpeg"
+	"image/png"
+	"io"
+	"my_blog/apperrors"
+	"my_blog/config"
+	"my_blog/i18n"
+	"my_blog/metrics"
+	"my_blog/models"
+	"regexp"
+)
+
+const (
+	maxUploadSize      = 2 * 1024 * 1024 // 上传文件大小上限
+	maxImageDimension  = 6000            // 原图单边像素上限
+	maxImagePixels     = 24000000        // 原图总像素上限，防止解压炸弹
+	jpegQuality        = 85
+	largeVariantSuffix = "_large"
+)
+
+var (
+	ErrUnsupportedImage = apperrors.Validation(i18n.MsgUnsupportedImage)
+	ErrImageTooLarge    = apperrors.Validation(i18n.MsgImageTooLarge)
+)
+
+// imageVariant 图片变体规格，maxSize 为长边像素上限
+type imageVariant struct {
+	suffix  string
+	maxSize int
+}
+
+var imageVariants = []imageVariant{
+	{suffix: "_thumb", maxSize: 150},
+	{suffix: "_medium", maxSize: 600},
+	{suffix: largeVariantSuffix, maxSize: 1920},
+}
+
+var (
+	variantPathPattern = regexp.MustCompile(`^(.+)` + largeVariantSuffix + `(\.(?:jpg|png))$`)
+	hashedPathPattern  = regexp.MustCompile(`(?:^|/)[0-9a-f]{2}/([0-9a-f]{64})_(?:thumb|medium|large)\.(?:jpg|png)$`)
+)
+
+// ImageService 图片处理服务
+type ImageService struct{}
+
+// ProcessAndSave 解码上传的图片，去除EXIF等元数据并重新编码为JPEG/PNG，
+// 按内容哈希生成 thumb/medium/large 三种尺寸保存，返回 large 变体的访问路径。
+// 相同内容的图片只保存一份，直接返回已有路径
+func (s *ImageService) ProcessAndSave(file io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(file, maxUploadSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxUploadSize {
+		return "", ErrImageTooLarge
+	}
+	metrics.UploadBytes.Add(float64(len(data)))
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if existing, err := findUploadByHash(hash); err != nil {
+		return "", err
+	} else if existing != "" {
+		return existing, nil
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrUnsupportedImage
+	}
+	if cfg.Width > maxImageDimension || cfg.Height > maxImageDimension || cfg.Width*cfg.Height > maxImagePixels {
+		return "", ErrImageTooLarge
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrUnsupportedImage
+	}
+
+	src := toRGBA(decoded)
+	if format == "jpeg" {
+		src = applyOrientation(src, jpegOrientation(data))
+	}
+
+	// 含透明通道的图片保存为PNG，其余统一为JPEG
+	ext := ".jpg"
+	if !src.Opaque() {
+		ext = ".png"
+	}
+
+	// 按哈希前两位分目录，避免单目录文件过多
+	key := hash[:2] + "/" + hash
+	for _, v := range imageVariants {
+		resized := fitWithin(src, v.maxSize)
+		if err := saveImage(key+v.suffix+ext, resized, ext); err != nil {
+			return "", err
+		}
+	}
+
+	path := config.UploadURLPrefix + key + largeVariantSuffix + ext
+	if err := recordUpload(hash, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ImageVariantsFor 根据 large 变体路径推导各尺寸变体的访问路径，非处理生成的图片返回nil
+func ImageVariantsFor(path string) *models.ImageVariants {
+	m := variantPathPattern.FindStringSubmatch(path)
+	if m == nil {
+		return nil
+	}
+	return &models.ImageVariants{
+		Thumbnail: m[1] + "_thumb" + m[2],
+		Medium:    m[1] + "_medium" + m[2],
+		Large:     path,
+	}
+}
+
+// UploadContentHash 返回按内容哈希命名的上传文件的哈希值，其他文件返回空字符串。
+// 此类文件内容永不改变，可长期缓存
+func UploadContentHash(path string) string {
+	m := hashedPathPattern.FindStringSubmatch(path)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// saveImage 按扩展名编码图片并写入存储后端
+func saveImage(key string, img image.Image, ext string) error {
+	var buf bytes.Buffer
+	contentType := "image/jpeg"
+	if ext == ".png" {
+		contentType = "image/png"
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+	} else if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return err
+	}
+	return config.Storage.Put(key, &buf, contentType)
+}
+
+// toRGBA 将任意颜色模型的图片转换为RGBA
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
+
+// fitWithin 等比缩小图片使长边不超过 maxSize，不放大
+func fitWithin(src *image.RGBA, maxSize int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w <= maxSize && h <= maxSize {
+		return src
+	}
+	if w >= h {
+		h = maxInt(1, h*maxSize/w)
+		w = maxSize
+	} else {
+		w = maxInt(1, w*maxSize/h)
+		h = maxSize
+	}
+	return resizeBox(src, w, h)
+}
+
+// resizeBox 使用区域平均（盒式滤波）缩小图片
+func resizeBox(src *image.RGBA, w, h int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for dy := 0; dy < h; dy++ {
+		y0 := dy * sh / h
+		y1 := maxInt(y0+1, (dy+1)*sh/h)
+		for dx := 0; dx < w; dx++ {
+			x0 := dx * sw / w
+			x1 := maxInt(x0+1, (dx+1)*sw/w)
+
+			var r, g, b, a, n uint64
+			for y := y0; y < y1; y++ {
+				off := src.PixOffset(x0, y)
+				for x := x0; x < x1; x++ {
+					r += uint64(src.Pix[off])
+					g += uint64(src.Pix[off+1])
+					b += uint64(src.Pix[off+2])
+					a += uint64(src.Pix[off+3])
+					off += 4
+					n++
+				}
+			}
+
+			off := dst.PixOffset(dx, dy)
+			dst.Pix[off] = uint8(r / n)
+			dst.Pix[off+1] = uint8(g / n)
+			dst.Pix[off+2] = uint8(b / n)
+			dst.Pix[off+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// applyOrientation 按EXIF方向值旋转/翻转图片，使去除元数据后显示方向不变
+func applyOrientation(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var nx, ny int
+			switch orientation {
+			case 2: // 水平翻转
+				nx, ny = w-1-x, y
+			case 3: // 旋转180°
+				nx, ny = w-1-x, h-1-y
+			case 4: // 垂直翻转
+				nx, ny = x, h-1-y
+			case 5: // 沿主对角线翻转
+				nx, ny = y, x
+			case 6: // 顺时针旋转90°
+				nx, ny = h-1-y, x
+			case 7: // 沿副对角线翻转
+				nx, ny = h-1-y, w-1-x
+			case 8: // 逆时针旋转90°
+				nx, ny = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(nx, ny):dst.PixOffset(nx, ny)+4], src.Pix[src.PixOffset(x, y):src.PixOffset(x, y)+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation 从JPEG的APP1(Exif)段读取方向标签，读取失败时返回1（正常方向）
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xDA { // 扫描数据开始，之后不再有元数据段
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		pos = end
+	}
+	return 1
+}
+
+// exifOrientation 在TIFF结构的IFD0中查找方向标签(0x0112)
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 1
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// background 跟踪进行中的后台任务，关闭时等待其完成
+	background sync.WaitGroup
+	// shuttingDown 服务进入关闭流程后置为true，就绪检查随即失败
+	shuttingDown atomic.Bool
+)
+
+// Go 启动一个后台任务，服务关闭时 WaitBackground 会等待它结束。
+// 长期运行的任务应在自己的 ctx 取消后返回
+func Go(fn func()) {
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		fn()
+	}()
+}
+
+// BeginShutdown 标记服务进入关闭流程，负载均衡据 /readyz 停止转发新请求
+func BeginShutdown() {
+	shuttingDown.Store(true)
+}
+
+// WaitBackground 等待所有后台任务完成，ctx 到期时返回 ctx 的错误
+func WaitBackground(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryDelay 第 attempts 次失败后的重试间隔：从 base 开始倍增，不超过 limit
+func retryDelay(base, limit time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
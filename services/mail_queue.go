@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"my_blog/config"
+	"my_blog/mailer"
+	"time"
+)
+
+// 邮件队列状态
+const (
+	mailPending = "pending"
+	mailSent    = "sent"
+	mailFailed  = "failed"
+)
+
+// maxMailRetryDelay 重试间隔上限
+const maxMailRetryDelay = 6 * time.Hour
+
+// enqueueMail 按收件人语言渲染模板并写入邮件队列，由 MailQueue 后台发送
+func enqueueMail(name string, recipient mailRecipient, data map[string]interface{}) error {
+	vars := map[string]interface{}{"Username": recipient.username}
+	for k, v := range data {
+		vars[k] = v
+	}
+
+	subject, body, err := renderMail(name, recipient.locale, vars)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = config.DB.Exec(`
+		INSERT INTO email_queue (recipient, template, subject, body, status, attempts, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, 0, ?, ?)
+	`, recipient.email, name, subject, body, mailPending, now, now)
+	return err
+}
+
+// MailQueue 邮件发送队列的后台处理，失败的邮件按指数退避重试，
+// 超过 config.MailMaxAttempts 次后标记为失败
+type MailQueue struct {
+	mailer mailer.Mailer
+	logger *slog.Logger
+}
+
+// NewMailQueue 创建邮件队列处理器，邮件通过 config.Mailer 发送
+func NewMailQueue(logger *slog.Logger) *MailQueue {
+	return &MailQueue{mailer: config.Mailer, logger: logger}
+}
+
+// queuedMail 队列中待发送的邮件
+type queuedMail struct {
+	id            int64
+	msg           mailer.Message
+	attempts      int
+	nextAttemptAt time.Time
+}
+
+// Run 按固定周期发送到期的邮件，直到 ctx 取消
+func (q *MailQueue) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := q.ProcessBatch(config.MailBatchSize); err != nil {
+				q.logger.Error("process mail queue failed", "error", err)
+			}
+		}
+	}
+}
+
+// ProcessBatch 发送最多 limit 封到期的邮件，返回成功发送的数量
+func (q *MailQueue) ProcessBatch(limit int) (int, error) {
+	rows, err := config.DB.Query(`
+		SELECT id, recipient, subject, body, attempts, next_attempt_at
+		FROM email_queue
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at
+		LIMIT ?
+	`, mailPending, time.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var batch []queuedMail
+	for rows.Next() {
+		var m queuedMail
+		if err := rows.Scan(&m.id, &m.msg.To, &m.msg.Subject, &m.msg.Body, &m.attempts, &m.nextAttemptAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batch = append(batch, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, m := range batch {
+		claimed, err := q.claim(m)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue // 已被其他实例领取
+		}
+		if q.deliver(m) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// claim 以租约方式领取邮件：把下次尝试时间推迟到租约结束，
+// 多个实例同时处理队列时只有一个能更新成功。发送进程中途退出时租约到期后会被重新发送
+func (q *MailQueue) claim(m queuedMail) (bool, error) {
+	result, err := config.DB.Exec(`
+		UPDATE email_queue SET next_attempt_at = ?
+		WHERE id = ? AND status = ? AND next_attempt_at = ?
+	`, time.Now().Add(config.MailClaimTimeout), m.id, mailPending, m.nextAttemptAt)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// deliver 发送邮件并记录结果，返回是否发送成功
+func (q *MailQueue) deliver(m queuedMail) bool {
+	sendErr := q.mailer.Send(m.msg)
+	attempts := m.attempts + 1
+	now := time.Now()
+
+	var err error
+	switch {
+	case sendErr == nil:
+		_, err = config.DB.Exec(
+			"UPDATE email_queue SET status = ?, attempts = ?, sent_at = ?, last_error = NULL WHERE id = ?",
+			mailSent, attempts, now, m.id,
+		)
+	case attempts >= config.MailMaxAttempts:
+		q.logger.Error("mail delivery failed permanently", "mail_id", m.id, "attempts", attempts, "error", sendErr)
+		_, err = config.DB.Exec(
+			"UPDATE email_queue SET status = ?, attempts = ?, last_error = ? WHERE id = ?",
+			mailFailed, attempts, sendErr.Error(), m.id,
+		)
+	default:
+		delay := mailRetryDelay(attempts)
+		q.logger.Warn("mail delivery failed, will retry", "mail_id", m.id, "attempts", attempts, "retry_in", delay, "error", sendErr)
+		_, err = config.DB.Exec(
+			"UPDATE email_queue SET attempts = ?, next_attempt_at = ?, last_error = ? WHERE id = ?",
+			attempts, now.Add(delay), sendErr.Error(), m.id,
+		)
+	}
+	if err != nil {
+		q.logger.Error("update mail status failed", "mail_id", m.id, "error", err)
+	}
+	return sendErr == nil
+}
+
+// mailRetryDelay 第 attempts 次失败后的重试间隔：从 config.MailRetryBackoff 开始倍增
+func mailRetryDelay(attempts int) time.Duration {
+	return retryDelay(config.MailRetryBackoff, maxMailRetryDelay, attempts)
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"my_blog/i18n"
+	"strings"
+	"text/template"
+)
+
+// 邮件模板名称
+const (
+	mailVerifyEmail     = "verify_email"
+	mailResetPassword   = "reset_password"
+	mailNewComment      = "new_comment"
+	mailCommentReply    = "comment_reply"
+	mailCommentRemoved  = "comment_removed"
+	mailArticleRemoved  = "article_removed"
+	mailPasswordChanged = "password_changed"
+	mailAccountLocked   = "account_locked"
+)
+
+// mailTemplate 邮件主题和正文模板
+type mailTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+func newMailTemplate(subject, body string) mailTemplate {
+	return mailTemplate{
+		subject: template.Must(template.New("subject").Parse(subject)),
+		body:    template.Must(template.New("body").Parse(body)),
+	}
+}
+
+// mailTemplates 各语言的邮件模板，缺少某语言时使用默认语言
+var mailTemplates = map[string]map[i18n.Locale]mailTemplate{
+	mailVerifyEmail: {
+		i18n.ZhCN: newMailTemplate("请验证你的邮箱", `{{.Username}}，你好：
+
+请在{{.Hours}}小时内打开以下链接完成邮箱验证：
+
+{{.Link}}
+
+如果这不是你本人的操作，请忽略此邮件。
+`),
+		i18n.En: newMailTemplate("Verify your email address", `Hi {{.Username}},
+
+Please open the following link within {{.Hours}} hours to verify your email address:
+
+{{.Link}}
+
+If you did not sign up, you can ignore this email.
+`),
+	},
+	mailResetPassword: {
+		i18n.ZhCN: newMailTemplate("重置你的密码", `{{.Username}}，你好：
+
+我们收到了重置密码的请求，请在{{.Minutes}}分钟内打开以下链接设置新密码：
+
+{{.Link}}
+
+如果这不是你本人的操作，请忽略此邮件，你的密码不会改变。
+`),
+		i18n.En: newMailTemplate("Reset your password", `Hi {{.Username}},
+
+We received a request to reset your password. Open the following link within {{.Minutes}} minutes to choose a new one:
+
+{{.Link}}
+
+If you did not request this, ignore this email and your password will stay the same.
+`),
+	},
+	mailNewComment: {
+		i18n.ZhCN: newMailTemplate("《{{.ArticleTitle}}》有新评论", `{{.Username}}，你好：
+
+{{.Commenter}} 评论了你的文章《{{.ArticleTitle}}》：
+
+{{.Content}}
+
+查看评论：{{.Link}}
+`),
+		i18n.En: newMailTemplate(`New comment on "{{.ArticleTitle}}"`, `Hi {{.Username}},
+
+{{.Commenter}} commented on your article "{{.ArticleTitle}}":
+
+{{.Content}}
+
+View the comment: {{.Link}}
+`),
+	},
+	mailCommentReply: {
+		i18n.ZhCN: newMailTemplate("{{.Commenter}} 回复了你的评论", `{{.Username}}，你好：
+
+{{.Commenter}} 在《{{.ArticleTitle}}》中回复了你的评论：
+
+{{.Content}}
+
+查看回复：{{.Link}}
+`),
+		i18n.En: newMailTemplate("{{.Commenter}} replied to your comment", `Hi {{.Username}},
+
+{{.Commenter}} replied to your comment on "{{.ArticleTitle}}":
+
+{{.Content}}
+
+View the reply: {{.Link}}
+`),
+	},
+	mailCommentRemoved: {
+		i18n.ZhCN: newMailTemplate("你的评论已被移除", `{{.Username}}，你好：
+
+你在《{{.ArticleTitle}}》下的评论已被管理员或文章作者移除：
+
+{{.Content}}
+`),
+		i18n.En: newMailTemplate("Your comment was removed", `Hi {{.Username}},
+
+Your comment on "{{.ArticleTitle}}" was removed by a moderator or the article author:
+
+{{.Content}}
+`),
+	},
+	mailArticleRemoved: {
+		i18n.ZhCN: newMailTemplate("你的文章已被移除", `{{.Username}}，你好：
+
+你的文章《{{.ArticleTitle}}》已被管理员移除。
+`),
+		i18n.En: newMailTemplate("Your article was removed", `Hi {{.Username}},
+
+Your article "{{.ArticleTitle}}" was removed by a moderator.
+`),
+	},
+	mailPasswordChanged: {
+		i18n.ZhCN: newMailTemplate("你的密码已修改", `{{.Username}}，你好：
+
+你的账号密码刚刚被修改。如果这不是你本人的操作，请立即通过找回密码重置密码。
+`),
+		i18n.En: newMailTemplate("Your password was changed", `Hi {{.Username}},
+
+The password for your account was just changed. If you did not do this, reset your password immediately.
+`),
+	},
+	mailAccountLocked: {
+		i18n.ZhCN: newMailTemplate("你的账号已被临时锁定", `{{.Username}}，你好：
+
+由于连续多次登录失败，你的账号已被锁定{{.Minutes}}分钟。如果这不是你本人的操作，建议通过找回密码重置密码。
+`),
+		i18n.En: newMailTemplate("Your account was temporarily locked", `Hi {{.Username}},
+
+Because of repeated failed login attempts your account has been locked for {{.Minutes}} minutes. If this wasn't you, consider resetting your password.
+`),
+	},
+}
+
+var subjectReplacer = strings.NewReplacer("\r", " ", "\n", " ")
+
+// renderMail 按收件人语言渲染邮件主题和正文
+func renderMail(name string, locale i18n.Locale, data map[string]interface{}) (string, string, error) {
+	templates, ok := mailTemplates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown mail template: %s", name)
+	}
+	tmpl, ok := templates[locale]
+	if !ok {
+		tmpl = templates[i18n.DefaultLocale]
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+	// 主题来自文章标题等用户输入，去掉换行避免破坏邮件头
+	return subjectReplacer.Replace(subject.String()), body.String(), nil
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"my_blog/config"
+	"my_blog/storage"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MediaService 上传文件引用管理与垃圾回收服务
+type MediaService struct {
+	logger *slog.Logger
+}
+
+// NewMediaService 创建上传文件服务
+func NewMediaService(logger *slog.Logger) *MediaService {
+	return &MediaService{logger: logger}
+}
+
+// findUploadByHash 按内容哈希查找已保存的上传文件，文件已不在存储中时视为不存在。
+// 查找前先刷新记录的 created_at，使命中的文件重新进入宽限期，
+// 避免在调用方 acquireUpload 之前被垃圾回收删除
+func findUploadByHash(hash string) (string, error) {
+	if _, err := config.DB.Exec("UPDATE uploads SET created_at = ? WHERE hash = ?", time.Now(), hash); err != nil {
+		return "", err
+	}
+
+	var path string
+	err := config.DB.QueryRow("SELECT path FROM uploads WHERE hash = ?", hash).Scan(&path)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	key, ok := uploadKey(path)
+	if !ok {
+		return "", nil
+	}
+	if _, err := config.Storage.Stat(key); err != nil {
+		return "", nil
+	}
+	return path, nil
+}
+
+// recordUpload 登记新保存的上传文件，初始引用计数为0
+func recordUpload(hash, path string) error {
+	_, err := config.DB.Exec(`
+		INSERT INTO uploads (hash, path, ref_count, created_at)
+		VALUES (?, ?, 0, ?)
+		ON DUPLICATE KEY UPDATE path = VALUES(path), created_at = VALUES(created_at)
+	`, hash, path, time.Now())
+	return err
+}
+
+// acquireUpload 增加上传文件的引用计数，非上传表中的路径（如默认头像）忽略
+func (s *MediaService) acquireUpload(path string) {
+	if path == "" {
+		return
+	}
+	if _, err := config.DB.Exec("UPDATE uploads SET ref_count = ref_count + 1 WHERE path = ?", path); err != nil {
+		s.logger.Error("acquire upload failed", "path", path, "error", err)
+	}
+}
+
+// releaseUpload 减少上传文件的引用计数，计数归零的文件由垃圾回收清理
+func (s *MediaService) releaseUpload(path string) {
+	if path == "" {
+		return
+	}
+	if _, err := config.DB.Exec("UPDATE uploads SET ref_count = GREATEST(ref_count - 1, 0) WHERE path = ?", path); err != nil {
+		s.logger.Error("release upload failed", "path", path, "error", err)
+	}
+}
+
+// replaceUpload 引用从 oldPath 切换到 newPath 时调整引用计数
+func (s *MediaService) replaceUpload(oldPath, newPath string) {
+	if oldPath == newPath {
+		return
+	}
+	s.releaseUpload(oldPath)
+	s.acquireUpload(newPath)
+}
+
+// uploadKey 将 /uploads/ 开头的访问路径转换为存储后端中的key
+func uploadKey(path string) (string, bool) {
+	if !strings.HasPrefix(path, config.UploadURLPrefix) {
+		return "", false
+	}
+	key, err := storage.CleanKey(strings.TrimPrefix(path, config.UploadURLPrefix))
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// uploadVariantPaths 返回上传文件本身及其所有尺寸变体的访问路径
+func uploadVariantPaths(path string) []string {
+	if v := ImageVariantsFor(path); v != nil {
+		return []string{v.Thumbnail, v.Medium, v.Large}
+	}
+	return []string{path}
+}
+
+// embeddedUploadPattern 正文中嵌入的上传图片地址（POST /uploads 生成的任一尺寸变体），
+// 分组为去掉尺寸后缀的路径和扩展名
+var embeddedUploadPattern = regexp.MustCompile(
+	regexp.QuoteMeta(config.UploadURLPrefix) + `([0-9a-f]{2}/[0-9a-f]{64})_(?:thumb|medium|large)(\.(?:jpg|png))`,
+)
+
+// embeddedUploads 返回正文中嵌入的上传图片对应的 large 变体路径（即上传表中登记的路径）
+func embeddedUploads(content string) []string {
+	var paths []string
+	for _, m := range embeddedUploadPattern.FindAllStringSubmatch(content, -1) {
+		paths = append(paths, config.UploadURLPrefix+m[1]+largeVariantSuffix+m[2])
+	}
+	return paths
+}
+
+// referencedUploads 收集用户和文章表中仍在引用的所有文件路径，包括文章和评论正文中嵌入的图片
+func referencedUploads() (map[string]bool, error) {
+	rows, err := config.DB.Query(`
+		SELECT image_data FROM users WHERE image_data IS NOT NULL
+		UNION SELECT background_image FROM users WHERE background_image IS NOT NULL
+		UNION SELECT image_path FROM articles WHERE image_path IS NOT NULL
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	referenced := make(map[string]bool)
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		for _, p := range uploadVariantPaths(path) {
+			referenced[p] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := addEmbeddedUploads(referenced); err != nil {
+		return nil, err
+	}
+	return referenced, nil
+}
+
+// addEmbeddedUploads 将文章和评论正文中嵌入的上传图片及其变体加入 referenced。
+// 回收站中的内容仍可恢复，其中的图片同样保留
+func addEmbeddedUploads(referenced map[string]bool) error {
+	like := "%" + config.UploadURLPrefix + "%"
+	rows, err := config.DB.Query(`
+		SELECT content FROM articles WHERE content LIKE ?
+		UNION ALL SELECT content FROM comments WHERE content LIKE ?
+	`, like, like)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var content string
+		if err := rows.Scan(&content); err != nil {
+			return err
+		}
+		for _, path := range embeddedUploads(content) {
+			for _, p := range uploadVariantPaths(path) {
+				referenced[p] = true
+			}
+		}
+	}
+	return rows.Err()
+}
+
+// CollectGarbage 按数据库中的实际引用重算引用计数，并删除超过宽限期且无人引用的上传文件，
+// 包括旧版本遗留的未登记文件。ref_count 只统计图片字段的引用，正文中嵌入的图片在每次回收时扫描。
+// 返回删除的文件数
+func (s *MediaService) CollectGarbage(gracePeriod time.Duration) (int, error) {
+	_, err := config.DB.Exec(`
+		UPDATE uploads u SET ref_count =
+			(SELECT COUNT(*) FROM users WHERE image_data = u.path) +
+			(SELECT COUNT(*) FROM users WHERE background_image = u.path) +
+			(SELECT COUNT(*) FROM articles WHERE image_path = u.path)
+	`)
+	if err != nil {
+		return 0, err
+	}
+
+	referenced, err := referencedUploads()
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-gracePeriod)
+	if err := deleteUnusedUploadRecords(cutoff, referenced); err != nil {
+		return 0, err
+	}
+
+	// 记录中仍存在的文件（宽限期内或仍被引用）不删除
+	registered, err := registeredUploads()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	err = config.Storage.List("", func(obj storage.Object) error {
+		path := config.UploadURLPrefix + obj.Key
+		if referenced[path] || registered[path] || isDefaultUpload(path) || obj.ModTime.After(cutoff) {
+			return nil
+		}
+		if err := config.Storage.Delete(obj.Key); err != nil {
+			s.logger.Warn("remove orphan upload failed", "key", obj.Key, "error", err)
+			return nil
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+// deleteUnusedUploadRecords 删除超过宽限期、ref_count 为0且未嵌入正文的上传记录
+func deleteUnusedUploadRecords(cutoff time.Time, referenced map[string]bool) error {
+	rows, err := config.DB.Query("SELECT path FROM uploads WHERE ref_count = 0 AND created_at < ?", cutoff)
+	if err != nil {
+		return err
+	}
+	var unused []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			rows.Close()
+			return err
+		}
+		if !referenced[path] {
+			unused = append(unused, path)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, path := range unused {
+		// 期间重新被引用或重新上传（刷新了 created_at）的记录不删除
+		_, err := config.DB.Exec("DELETE FROM uploads WHERE path = ? AND ref_count = 0 AND created_at < ?", path, cutoff)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registeredUploads 返回上传表中所有文件（含变体）的访问路径
+func registeredUploads() (map[string]bool, error) {
+	rows, err := config.DB.Query("SELECT path FROM uploads")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	registered := make(map[string]bool)
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		for _, p := range uploadVariantPaths(path) {
+			registered[p] = true
+		}
+	}
+	return registered, rows.Err()
+}
+
+// isDefaultUpload 默认头像和背景图由表结构默认值引用，永不清理
+func isDefaultUpload(p string) bool {
+	return strings.HasPrefix(path.Base(p), "default_")
+}
+
+// RunGarbageCollector 按固定周期执行上传文件垃圾回收，直到 ctx 取消
+func (s *MediaService) RunGarbageCollector(ctx context.Context, interval, gracePeriod time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := s.CollectGarbage(gracePeriod)
+			if err != nil {
+				s.logger.Error("upload garbage collection failed", "error", err)
+				continue
+			}
+			s.logger.Info("upload garbage collection finished", "removed", removed)
+		}
+	}
+}
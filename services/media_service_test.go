@@ -0,0 +1,97 @@
+package services
+
+import (
+	"my_blog/config"
+	"my_blog/storage"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestEmbeddedUploads(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	large := "/uploads/ab/" + hash + "_large.jpg"
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"none", "纯文本", nil},
+		{"markdown image", "![图](" + large + ")", []string{large}},
+		{"other variant", `<img src="/uploads/ab/` + hash + `_thumb.jpg">`, []string{large}},
+		{"absolute url", "https://blog.example.com/uploads/ab/" + hash + "_medium.png", []string{"/uploads/ab/" + hash + "_large.png"}},
+		{"several", large + " and " + "/uploads/cd/" + strings.Repeat("cd", 32) + "_large.jpg",
+			[]string{large, "/uploads/cd/" + strings.Repeat("cd", 32) + "_large.jpg"}},
+		{"default avatar", "/uploads/default_avatar.jpg", nil},
+		{"not a hash", "/uploads/ab/photo_large.jpg", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := embeddedUploads(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("embeddedUploads = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectGarbageKeepsEmbeddedUploads(t *testing.T) {
+	dir := t.TempDir()
+	old := config.Storage
+	config.Storage = storage.NewLocalStorage(dir, config.UploadURLPrefix, "test")
+	t.Cleanup(func() { config.Storage = old })
+
+	embedded := strings.Repeat("ab", 32)
+	unused := strings.Repeat("cd", 32)
+	longAgo := time.Now().Add(-48 * time.Hour)
+	for _, hash := range []string{embedded, unused} {
+		for _, suffix := range []string{"_thumb", "_medium", "_large"} {
+			file := filepath.Join(dir, hash[:2], hash+suffix+".jpg")
+			if err := os.MkdirAll(filepath.Dir(file), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(file, []byte("jpeg"), 0640); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(file, longAgo, longAgo); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	embeddedPath := "/uploads/ab/" + embedded + "_large.jpg"
+	unusedPath := "/uploads/cd/" + unused + "_large.jpg"
+
+	mock := useMockDB(t)
+	mock.ExpectExec("UPDATE uploads u SET ref_count =").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SELECT image_data FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"path"}))
+	mock.ExpectQuery("SELECT content FROM articles WHERE content LIKE \\?\\s+UNION ALL SELECT content FROM comments").
+		WithArgs("%/uploads/%", "%/uploads/%").
+		WillReturnRows(sqlmock.NewRows([]string{"content"}).AddRow("![图](/uploads/ab/" + embedded + "_medium.jpg)"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT path FROM uploads WHERE ref_count = 0 AND created_at < ?")).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"path"}).AddRow(embeddedPath).AddRow(unusedPath))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM uploads WHERE path = ? AND ref_count = 0 AND created_at < ?")).
+		WithArgs(unusedPath, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT path FROM uploads")).
+		WillReturnRows(sqlmock.NewRows([]string{"path"}).AddRow(embeddedPath))
+
+	removed, err := NewMediaService(nil).CollectGarbage(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("CollectGarbage: %v", err)
+	}
+	if removed != 3 {
+		t.Errorf("removed = %d, want the 3 variants of the unused upload", removed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ab", embedded+"_thumb.jpg")); err != nil {
+		t.Errorf("embedded upload deleted: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cd", unused+"_large.jpg")); !os.IsNotExist(err) {
+		t.Errorf("unused upload kept: %v", err)
+	}
+}
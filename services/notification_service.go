@@ -0,0 +1,377 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"my_blog/config"
+	"my_blog/i18n"
+	"my_blog/models"
+	"my_blog/realtime"
+	"strconv"
+	"time"
+)
+
+// 通知偏好对应 notification_preferences 表中的列
+const (
+	prefComment    = "email_comment"
+	prefReply      = "email_reply"
+	prefModeration = "email_moderation"
+	prefAccount    = "email_account"
+)
+
+// 站内通知类型
+const (
+	NotificationComment        = "comment"
+	NotificationReply          = "reply"
+	NotificationCommentRemoved = "comment_removed"
+	NotificationArticleRemoved = "article_removed"
+	NotificationRoleChanged    = "role_changed"
+	NotificationStatusChanged  = "status_changed"
+)
+
+// NotificationService 根据业务事件生成站内通知，并按用户通知偏好发送邮件（写入队列由 MailQueue 发送）。
+// 通知失败只记录日志，不影响触发通知的业务操作
+type NotificationService struct {
+	logger *slog.Logger
+}
+
+// NewNotificationService 创建通知服务
+func NewNotificationService(logger *slog.Logger) *NotificationService {
+	return &NotificationService{logger: logger}
+}
+
+// mailRecipient 收件用户信息
+type mailRecipient struct {
+	id       int
+	username string
+	email    string
+	locale   i18n.Locale
+}
+
+// mailLocale 邮件使用用户的偏好语言，未设置时使用默认语言
+func mailLocale(preference sql.NullString) i18n.Locale {
+	if locale, ok := i18n.Parse(preference.String); ok {
+		return locale
+	}
+	return i18n.DefaultLocale
+}
+
+// GetPreferences 获取用户的通知偏好，未设置过时返回默认值（全部开启）
+func (s *NotificationService) GetPreferences(userID int) (*models.NotificationPreferences, error) {
+	prefs := models.NotificationPreferences{
+		EmailComment:    true,
+		EmailReply:      true,
+		EmailModeration: true,
+		EmailAccount:    true,
+	}
+	err := config.DB.QueryRow(`
+		SELECT email_comment, email_reply, email_moderation, email_account
+		FROM notification_preferences WHERE user_id = ?
+	`, userID).Scan(&prefs.EmailComment, &prefs.EmailReply, &prefs.EmailModeration, &prefs.EmailAccount)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// UpdatePreferences 保存用户的通知偏好
+func (s *NotificationService) UpdatePreferences(userID int, prefs *models.NotificationPreferences) error {
+	_, err := config.DB.Exec(`
+		INSERT INTO notification_preferences (user_id, email_comment, email_reply, email_moderation, email_account)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			email_comment = VALUES(email_comment),
+			email_reply = VALUES(email_reply),
+			email_moderation = VALUES(email_moderation),
+			email_account = VALUES(email_account)
+	`, userID, prefs.EmailComment, prefs.EmailReply, prefs.EmailModeration, prefs.EmailAccount)
+	if isMissingReference(err) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// CommentCreated 通知文章作者有新评论；回复评论时通知被回复的用户。
+// 评论者本人不会收到通知，同一用户只收到一条
+func (s *NotificationService) CommentCreated(comment *models.Comment) {
+	title, authorID, err := articleTitleAndAuthor(comment.ArticleID)
+	if err != nil {
+		s.logger.Error("load article for notification failed", "article_id", comment.ArticleID, "error", err)
+		return
+	}
+
+	mail := map[string]interface{}{
+		"ArticleTitle": title,
+		"Commenter":    comment.Author,
+		"Content":      comment.Content,
+		"Link":         articleLink(comment.ArticleID),
+	}
+	inbox := map[string]interface{}{
+		"article_title": title,
+		"commenter":     comment.Author,
+		"excerpt":       excerpt(comment.Content),
+	}
+
+	replyTo := 0
+	if comment.ParentID != nil {
+		var parentUserID sql.NullInt64
+		err := config.DB.QueryRow("SELECT user_id FROM comments WHERE id = ?", *comment.ParentID).Scan(&parentUserID)
+		if err != nil && err != sql.ErrNoRows {
+			s.logger.Error("load parent comment for notification failed", "comment_id", *comment.ParentID, "error", err)
+		}
+		replyTo = int(parentUserID.Int64)
+	}
+
+	if replyTo != 0 && replyTo != comment.UserID {
+		s.addToInbox(replyTo, NotificationReply, comment.ArticleID, comment.ID, inbox)
+		s.notify(replyTo, prefReply, mailCommentReply, mail)
+	}
+	if authorID != 0 && authorID != comment.UserID && authorID != replyTo {
+		s.addToInbox(authorID, NotificationComment, comment.ArticleID, comment.ID, inbox)
+		s.notify(authorID, prefComment, mailNewComment, mail)
+	}
+}
+
+// CommentRemoved 通知评论作者其评论已被他人移除
+func (s *NotificationService) CommentRemoved(comment *models.Comment) {
+	title, _, err := articleTitleAndAuthor(comment.ArticleID)
+	if err != nil {
+		s.logger.Error("load article for notification failed", "article_id", comment.ArticleID, "error", err)
+		return
+	}
+	s.addToInbox(comment.UserID, NotificationCommentRemoved, comment.ArticleID, 0, map[string]interface{}{
+		"article_title": title,
+		"excerpt":       excerpt(comment.Content),
+	})
+	s.notify(comment.UserID, prefModeration, mailCommentRemoved, map[string]interface{}{
+		"ArticleTitle": title,
+		"Content":      comment.Content,
+	})
+}
+
+// ArticleRemoved 通知文章作者其文章已被他人删除，文章已不存在，只记录标题
+func (s *NotificationService) ArticleRemoved(authorID int, title string) {
+	s.addToInbox(authorID, NotificationArticleRemoved, 0, 0, map[string]interface{}{
+		"article_title": title,
+	})
+	s.notify(authorID, prefModeration, mailArticleRemoved, map[string]interface{}{
+		"ArticleTitle": title,
+	})
+}
+
+// RoleChanged 通知用户其角色已被管理员修改
+func (s *NotificationService) RoleChanged(userID, roleID int) {
+	var roleName string
+	if err := config.DB.QueryRow("SELECT name FROM roles WHERE id = ?", roleID).Scan(&roleName); err != nil && err != sql.ErrNoRows {
+		s.logger.Error("load role for notification failed", "role_id", roleID, "error", err)
+	}
+	s.addToInbox(userID, NotificationRoleChanged, 0, 0, map[string]interface{}{
+		"role_id":   roleID,
+		"role_name": roleName,
+	})
+}
+
+// StatusChanged 通知用户其账号状态已被管理员修改
+func (s *NotificationService) StatusChanged(userID int, change *models.UserStatusChange) {
+	data := map[string]interface{}{
+		"status": change.Status,
+	}
+	if change.Reason != "" {
+		data["reason"] = change.Reason
+	}
+	if change.Until != nil {
+		data["until"] = change.Until
+	}
+	s.addToInbox(userID, NotificationStatusChanged, 0, 0, data)
+}
+
+// PasswordChanged 通知用户密码已被修改
+func (s *NotificationService) PasswordChanged(userID int) {
+	s.notify(userID, prefAccount, mailPasswordChanged, nil)
+}
+
+// AccountLocked 通知用户账号因多次登录失败被锁定
+func (s *NotificationService) AccountLocked(userID int) {
+	s.notify(userID, prefAccount, mailAccountLocked, map[string]interface{}{
+		"Minutes": int(config.LoginLockoutDuration.Minutes()),
+	})
+}
+
+// addToInbox 写入站内通知并实时推送给在线的用户，articleID、commentID 为0时不关联
+func (s *NotificationService) addToInbox(userID int, kind string, articleID, commentID int, data map[string]interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		s.logger.Error("encode notification failed", "user_id", userID, "type", kind, "error", err)
+		return
+	}
+
+	notification := models.Notification{
+		Type:      kind,
+		ArticleID: idPtr(articleID),
+		CommentID: idPtr(commentID),
+		Data:      data,
+		CreateAt:  time.Now(),
+	}
+	result, err := config.DB.Exec(`
+		INSERT INTO notifications (user_id, type, article_id, comment_id, data, create_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, userID, kind, notification.ArticleID, notification.CommentID, payload, notification.CreateAt)
+	if err == nil {
+		notification.ID, err = result.LastInsertId()
+	}
+	if err != nil {
+		s.logger.Error("create notification failed", "user_id", userID, "type", kind, "error", err)
+		return
+	}
+
+	publish(realtime.UserTopic(userID), EventNotification, notification)
+}
+
+// List 获取用户的站内通知，按时间倒序，unreadOnly 为true时只返回未读通知
+func (s *NotificationService) List(userID int, unreadOnly bool, limit, offset int) (*models.NotificationList, error) {
+	query := `
+		SELECT id, type, article_id, comment_id, data, read_at IS NOT NULL, create_at
+		FROM notifications
+		WHERE user_id = ?`
+	if unreadOnly {
+		query += " AND read_at IS NULL"
+	}
+	query += " ORDER BY create_at DESC, id DESC LIMIT ? OFFSET ?"
+
+	rows, err := config.DB.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := &models.NotificationList{Items: []models.Notification{}}
+	for rows.Next() {
+		var n models.Notification
+		var articleID, commentID sql.NullInt64
+		var data []byte
+		if err := rows.Scan(&n.ID, &n.Type, &articleID, &commentID, &data, &n.Read, &n.CreateAt); err != nil {
+			return nil, err
+		}
+		n.ArticleID = optionalID(articleID)
+		n.CommentID = optionalID(commentID)
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &n.Data); err != nil {
+				return nil, err
+			}
+		}
+		list.Items = append(list.Items, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = config.DB.QueryRow(
+		"SELECT COUNT(*) FROM notifications WHERE user_id = ? AND read_at IS NULL", userID,
+	).Scan(&list.UnreadCount)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// MarkRead 将用户的一条通知标记为已读，通知不存在或不属于该用户时返回 ErrNotificationNotFound
+func (s *NotificationService) MarkRead(userID int, id int64) error {
+	var readAt sql.NullTime
+	err := config.DB.QueryRow(
+		"SELECT read_at FROM notifications WHERE id = ? AND user_id = ?", id, userID,
+	).Scan(&readAt)
+	if err == sql.ErrNoRows {
+		return ErrNotificationNotFound
+	}
+	if err != nil || readAt.Valid {
+		return err
+	}
+
+	_, err = config.DB.Exec("UPDATE notifications SET read_at = ? WHERE id = ? AND read_at IS NULL", time.Now(), id)
+	return err
+}
+
+// MarkAllRead 将用户的所有未读通知标记为已读，返回标记的数量
+func (s *NotificationService) MarkAllRead(userID int) (int64, error) {
+	result, err := config.DB.Exec(
+		"UPDATE notifications SET read_at = ? WHERE user_id = ? AND read_at IS NULL", time.Now(), userID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// notify 用户开启了 pref 对应的通知时，将模板邮件写入发送队列
+func (s *NotificationService) notify(userID int, pref, template string, data map[string]interface{}) {
+	recipient, enabled, err := loadRecipient(userID, pref)
+	if err == sql.ErrNoRows {
+		return
+	}
+	if err == nil && enabled {
+		err = enqueueMail(template, recipient, data)
+	}
+	if err != nil {
+		s.logger.Error("queue notification failed", "user_id", userID, "template", template, "error", err)
+	}
+}
+
+// loadRecipient 读取收件人信息及其对 pref 通知的设置
+func loadRecipient(userID int, pref string) (mailRecipient, bool, error) {
+	var recipient mailRecipient
+	var locale sql.NullString
+	var enabled bool
+	// pref 只会是本文件中定义的列名常量
+	err := config.DB.QueryRow(fmt.Sprintf(`
+		SELECT u.id, u.username, u.email, u.locale, COALESCE(p.%s, TRUE)
+		FROM users u
+		LEFT JOIN notification_preferences p ON p.user_id = u.id
+		WHERE u.id = ?
+	`, pref), userID).Scan(&recipient.id, &recipient.username, &recipient.email, &locale, &enabled)
+	recipient.locale = mailLocale(locale)
+	return recipient, enabled, err
+}
+
+// articleTitleAndAuthor 读取文章标题和作者的用户ID（作者没有账号时为0）
+func articleTitleAndAuthor(articleID int) (string, int, error) {
+	var title string
+	var authorID sql.NullInt64
+	err := config.DB.QueryRow("SELECT title, author_id FROM articles WHERE id = ?", articleID).Scan(&title, &authorID)
+	return title, int(authorID.Int64), err
+}
+
+// idPtr 将ID转换为指针，0表示不关联
+func idPtr(id int) *int {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+// optionalID 将可为NULL的ID转换为指针
+func optionalID(id sql.NullInt64) *int {
+	if !id.Valid {
+		return nil
+	}
+	v := int(id.Int64)
+	return &v
+}
+
+// excerptLength 站内通知中评论摘要的最大字符数
+const excerptLength = 100
+
+// excerpt 截取内容摘要
+func excerpt(content string) string {
+	runes := []rune(content)
+	if len(runes) <= excerptLength {
+		return content
+	}
+	return string(runes[:excerptLength]) + "…"
+}
+
+// articleLink 前端文章详情页地址
+func articleLink(articleID int) string {
+	return config.AppBaseURL + "/blog/" + strconv.Itoa(articleID)
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"my_blog/models"
+	"strings"
+)
+
+// updateSet 拼接部分更新的 SET 子句，只包含补丁中出现的字段
+type updateSet struct {
+	assignments []string
+	args        []interface{}
+}
+
+// add 添加一条赋值语句及其参数
+func (u *updateSet) add(assignment string, args ...interface{}) {
+	u.assignments = append(u.assignments, assignment)
+	u.args = append(u.args, args...)
+}
+
+// addOptional 字段出现时更新对应列，显式为 null 时写入 NULL
+func (u *updateSet) addOptional(column string, value models.Optional[string]) {
+	if !value.Set {
+		return
+	}
+	u.add(column+" = ?", nullableString(value))
+}
+
+// empty 补丁中没有任何字段
+func (u *updateSet) empty() bool {
+	return len(u.assignments) == 0
+}
+
+// clause 返回 SET 之后的赋值列表
+func (u *updateSet) clause() string {
+	return strings.Join(u.assignments, ", ")
+}
+
+// nullableString 显式为 null 的字段返回nil，写入数据库时为 NULL
+func nullableString(value models.Optional[string]) interface{} {
+	if value.Null {
+		return nil
+	}
+	return value.Value
+}
+
+// mergeSocialLinks 将社交链接的部分更新合并到当前链接上
+func mergeSocialLinks(links models.SocialLinks, patch models.SocialLinksPatch) models.SocialLinks {
+	merge := func(current *string, value models.Optional[string]) {
+		if value.Set {
+			*current = value.Value
+		}
+	}
+	merge(&links.GitHub, patch.GitHub)
+	merge(&links.Twitter, patch.Twitter)
+	merge(&links.Weibo, patch.Weibo)
+	merge(&links.Zhihu, patch.Zhihu)
+	merge(&links.LinkedIn, patch.LinkedIn)
+	return links
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"my_blog/config"
+	"my_blog/models"
+	"strings"
+	"time"
+)
+
+// ReactionLike 点赞，其余为表情反应
+const ReactionLike = "like"
+
+// ReactionTypes 支持的反应类型
+var ReactionTypes = []string{ReactionLike, "love", "laugh", "wow", "sad", "celebrate"}
+
+// reactionTarget 可被反应的内容：反应表、其指向内容的列，以及判断内容是否可见（不在回收站中）的函数
+type reactionTarget struct {
+	table    string
+	column   string
+	exists   func(id int) (bool, error)
+	notFound error
+}
+
+var (
+	articleReactions = reactionTarget{table: "article_reactions", column: "article_id", exists: articleExists, notFound: ErrArticleNotFound}
+	commentReactions = reactionTarget{table: "comment_reactions", column: "comment_id", exists: commentExists, notFound: ErrCommentNotFound}
+)
+
+// ReactionService 文章和评论的反应（点赞和表情）
+type ReactionService struct{}
+
+// ToggleArticleReaction 切换用户对文章的反应，返回切换后是否存在该反应及文章最新的反应统计
+func (s *ReactionService) ToggleArticleReaction(articleID, userID int, reaction string) (bool, *models.ReactionSummary, error) {
+	return s.toggle(articleReactions, articleID, userID, reaction)
+}
+
+// ToggleCommentReaction 切换用户对评论的反应，返回切换后是否存在该反应及评论最新的反应统计
+func (s *ReactionService) ToggleCommentReaction(commentID, userID int, reaction string) (bool, *models.ReactionSummary, error) {
+	return s.toggle(commentReactions, commentID, userID, reaction)
+}
+
+// toggle 已有该反应时删除，否则添加
+func (s *ReactionService) toggle(target reactionTarget, id, userID int, reaction string) (bool, *models.ReactionSummary, error) {
+	if !isReactionType(reaction) {
+		return false, nil, ErrInvalidReaction
+	}
+	exists, err := target.exists(id)
+	if err != nil {
+		return false, nil, err
+	}
+	if !exists {
+		return false, nil, target.notFound
+	}
+
+	result, err := config.DB.Exec(
+		"DELETE FROM "+target.table+" WHERE "+target.column+" = ? AND user_id = ? AND reaction = ?",
+		id, userID, reaction,
+	)
+	if err != nil {
+		return false, nil, err
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, nil, err
+	}
+
+	if removed == 0 {
+		// 并发的重复请求由主键去重；不用 INSERT IGNORE，它会把外键错误也降为警告
+		_, err = config.DB.Exec(
+			"INSERT INTO "+target.table+" ("+target.column+", user_id, reaction, created_at) VALUES (?, ?, ?, ?)"+
+				" ON DUPLICATE KEY UPDATE created_at = created_at",
+			id, userID, reaction, time.Now(),
+		)
+		if isMissingReference(err) {
+			return false, nil, target.notFound
+		}
+		if err != nil {
+			return false, nil, err
+		}
+	}
+
+	summaries, err := s.summaries(target, []int{id}, userID)
+	if err != nil {
+		return false, nil, err
+	}
+	return removed == 0, summaries[id], nil
+}
+
+// attachToArticles 为文章填充反应统计，viewerID 为0表示未登录
+func (s *ReactionService) attachToArticles(articles []models.Article, viewerID int) error {
+	ids := make([]int, len(articles))
+	for i := range articles {
+		ids[i] = articles[i].ID
+	}
+	summaries, err := s.summaries(articleReactions, ids, viewerID)
+	if err != nil {
+		return err
+	}
+	for i := range articles {
+		articles[i].ReactionSummary = summaries[articles[i].ID]
+	}
+	return nil
+}
+
+// attachToComments 为评论填充反应统计，viewerID 为0表示未登录
+func (s *ReactionService) attachToComments(comments []models.Comment, viewerID int) error {
+	ids := make([]int, len(comments))
+	for i := range comments {
+		ids[i] = comments[i].ID
+	}
+	summaries, err := s.summaries(commentReactions, ids, viewerID)
+	if err != nil {
+		return err
+	}
+	for i := range comments {
+		comments[i].ReactionSummary = summaries[comments[i].ID]
+	}
+	return nil
+}
+
+// summaries 批量统计内容的反应数量及 viewerID 的反应，每个ID都有结果
+func (s *ReactionService) summaries(target reactionTarget, ids []int, viewerID int) (map[int]*models.ReactionSummary, error) {
+	result := make(map[int]*models.ReactionSummary, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		result[id] = &models.ReactionSummary{Reactions: map[string]int{}, MyReactions: []string{}}
+		args[i] = id
+	}
+	in := "(" + strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",") + ")"
+
+	rows, err := config.DB.Query(
+		"SELECT "+target.column+", reaction, COUNT(*) FROM "+target.table+
+			" WHERE "+target.column+" IN "+in+" GROUP BY "+target.column+", reaction",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, count int
+		var reaction string
+		if err := rows.Scan(&id, &reaction, &count); err != nil {
+			return nil, err
+		}
+		result[id].Reactions[reaction] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if viewerID == 0 {
+		return result, nil
+	}
+
+	mine, err := config.DB.Query(
+		"SELECT "+target.column+", reaction FROM "+target.table+
+			" WHERE user_id = ? AND "+target.column+" IN "+in,
+		append([]interface{}{viewerID}, args...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer mine.Close()
+	for mine.Next() {
+		var id int
+		var reaction string
+		if err := mine.Scan(&id, &reaction); err != nil {
+			return nil, err
+		}
+		summary := result[id]
+		summary.MyReactions = append(summary.MyReactions, reaction)
+		summary.LikedByMe = summary.LikedByMe || reaction == ReactionLike
+	}
+	return result, mine.Err()
+}
+
+// isReactionType 判断是否为支持的反应类型
+func isReactionType(reaction string) bool {
+	for _, t := range ReactionTypes {
+		if t == reaction {
+			return true
+		}
+	}
+	return false
+}
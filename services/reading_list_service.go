@@ -0,0 +1,290 @@
+package services
+
+import (
+	"database/sql"
+	"my_blog/config"
+	"my_blog/models"
+	"time"
+)
+
+// ReadingListService 用户的阅读列表：有名称、可排序的文章集合，只有所有者可以访问
+type ReadingListService struct {
+	reactions *ReactionService
+}
+
+// NewReadingListService 创建阅读列表服务
+func NewReadingListService() *ReadingListService {
+	return &ReadingListService{reactions: &ReactionService{}}
+}
+
+// GetReadingLists 获取用户的所有阅读列表及其文章数量（不含回收站中的文章），不包含文章
+func (s *ReadingListService) GetReadingLists(userID int) ([]models.ReadingList, error) {
+	rows, err := config.DB.Query(`
+		SELECT l.id, l.name, COALESCE(l.description, ''), l.created_at, l.updated_at, COUNT(a.id)
+		FROM reading_lists l
+		LEFT JOIN reading_list_items i ON i.list_id = l.id
+		LEFT JOIN articles a ON a.id = i.article_id AND a.deleted_at IS NULL
+		WHERE l.user_id = ?
+		GROUP BY l.id
+		ORDER BY l.created_at, l.id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := []models.ReadingList{}
+	for rows.Next() {
+		var list models.ReadingList
+		if err := rows.Scan(&list.ID, &list.Name, &list.Description, &list.CreatedAt, &list.UpdatedAt, &list.ArticleCount); err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	return lists, rows.Err()
+}
+
+// GetReadingList 获取用户的一个阅读列表，文章按列表内的顺序返回
+func (s *ReadingListService) GetReadingList(userID, id int) (*models.ReadingList, error) {
+	var list models.ReadingList
+	err := config.DB.QueryRow(`
+		SELECT id, name, COALESCE(description, ''), created_at, updated_at
+		FROM reading_lists
+		WHERE id = ? AND user_id = ?
+	`, id, userID).Scan(&list.ID, &list.Name, &list.Description, &list.CreatedAt, &list.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrReadingListNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT `+articleColumns+`
+		FROM reading_list_items i
+		JOIN articles a ON a.id = i.article_id
+		LEFT JOIN categories c ON a.category_id = c.id
+		WHERE i.list_id = ? AND a.deleted_at IS NULL
+		ORDER BY i.position
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list.Articles = []models.Article{}
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		list.Articles = append(list.Articles, article)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.reactions.attachToArticles(list.Articles, userID); err != nil {
+		return nil, err
+	}
+	list.ArticleCount = len(list.Articles)
+	return &list, nil
+}
+
+// CreateReadingList 创建阅读列表
+func (s *ReadingListService) CreateReadingList(userID int, list *models.ReadingList) error {
+	now := time.Now()
+	result, err := config.DB.Exec(`
+		INSERT INTO reading_lists (user_id, name, description, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, userID, list.Name, list.Description, now, now)
+	if isDuplicateEntry(err) {
+		return ErrReadingListExists
+	}
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	list.ID = int(id)
+	list.CreatedAt = now
+	list.UpdatedAt = now
+	return nil
+}
+
+// UpdateReadingList 更新阅读列表的名称和描述
+func (s *ReadingListService) UpdateReadingList(userID, id int, list *models.ReadingList) error {
+	if err := s.checkOwner(userID, id); err != nil {
+		return err
+	}
+
+	_, err := config.DB.Exec(`
+		UPDATE reading_lists
+		SET name = ?, description = ?, updated_at = ?
+		WHERE id = ?
+	`, list.Name, list.Description, time.Now(), id)
+	if isDuplicateEntry(err) {
+		return ErrReadingListExists
+	}
+	return err
+}
+
+// DeleteReadingList 删除阅读列表，列表中的文章本身不受影响
+func (s *ReadingListService) DeleteReadingList(userID, id int) error {
+	result, err := config.DB.Exec("DELETE FROM reading_lists WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrReadingListNotFound
+	}
+
+	return nil
+}
+
+// AddArticle 将文章添加到阅读列表末尾，已在列表中时保持原位置
+func (s *ReadingListService) AddArticle(userID, id, articleID int) error {
+	if err := s.checkOwner(userID, id); err != nil {
+		return err
+	}
+
+	exists, err := articleExists(articleID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrArticleNotFound
+	}
+
+	var position int
+	err = config.DB.QueryRow(
+		"SELECT COALESCE(MAX(position), 0) + 1 FROM reading_list_items WHERE list_id = ?", id,
+	).Scan(&position)
+	if err != nil {
+		return err
+	}
+
+	// INSERT IGNORE 会把外键错误也降为警告，这里用 ON DUPLICATE KEY 忽略重复添加
+	now := time.Now()
+	_, err = config.DB.Exec(`
+		INSERT INTO reading_list_items (list_id, article_id, position, added_at)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE position = position
+	`, id, articleID, position, now)
+	if isMissingReference(err) {
+		return ErrArticleNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return s.touch(id, now)
+}
+
+// RemoveArticle 从阅读列表中移除文章，文章不在列表中时不报错
+func (s *ReadingListService) RemoveArticle(userID, id, articleID int) error {
+	if err := s.checkOwner(userID, id); err != nil {
+		return err
+	}
+
+	_, err := config.DB.Exec("DELETE FROM reading_list_items WHERE list_id = ? AND article_id = ?", id, articleID)
+	if err != nil {
+		return err
+	}
+	return s.touch(id, time.Now())
+}
+
+// ReorderArticles 按 articleIDs 的顺序重新排列阅读列表，articleIDs 必须恰好包含列表中所有不在回收站中的文章
+func (s *ReadingListService) ReorderArticles(userID, id int, articleIDs []int) error {
+	if err := s.checkOwner(userID, id); err != nil {
+		return err
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 锁定列表条目，防止与并发的添加、移除交错；回收站中的文章对用户不可见，不参与排序
+	rows, err := tx.Query(`
+		SELECT i.article_id FROM reading_list_items i
+		JOIN articles a ON a.id = i.article_id
+		WHERE i.list_id = ? AND a.deleted_at IS NULL
+		FOR UPDATE
+	`, id)
+	if err != nil {
+		return err
+	}
+	current := make(map[int]bool)
+	for rows.Next() {
+		var articleID int
+		if err := rows.Scan(&articleID); err != nil {
+			rows.Close()
+			return err
+		}
+		current[articleID] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(articleIDs) != len(current) {
+		return ErrReadingListOrder
+	}
+	seen := make(map[int]bool, len(articleIDs))
+	for _, articleID := range articleIDs {
+		if !current[articleID] || seen[articleID] {
+			return ErrReadingListOrder
+		}
+		seen[articleID] = true
+	}
+
+	for i, articleID := range articleIDs {
+		_, err := tx.Exec(
+			"UPDATE reading_list_items SET position = ? WHERE list_id = ? AND article_id = ?",
+			i+1, id, articleID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	// 回收站中的文章排到最后，恢复后出现在列表末尾
+	_, err = tx.Exec(`
+		UPDATE reading_list_items i
+		JOIN articles a ON a.id = i.article_id
+		SET i.position = i.position + ?
+		WHERE i.list_id = ? AND a.deleted_at IS NOT NULL
+	`, len(articleIDs), id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE reading_lists SET updated_at = ? WHERE id = ?", time.Now(), id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkOwner 阅读列表不存在或不属于该用户时返回 ErrReadingListNotFound
+func (s *ReadingListService) checkOwner(userID, id int) error {
+	var ownerID int
+	err := config.DB.QueryRow("SELECT user_id FROM reading_lists WHERE id = ?", id).Scan(&ownerID)
+	if err == sql.ErrNoRows || (err == nil && ownerID != userID) {
+		return ErrReadingListNotFound
+	}
+	return err
+}
+
+// touch 更新阅读列表的修改时间
+func (s *ReadingListService) touch(id int, now time.Time) error {
+	_, err := config.DB.Exec("UPDATE reading_lists SET updated_at = ? WHERE id = ?", now, id)
+	return err
+}
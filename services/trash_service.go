@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"my_blog/config"
+	"my_blog/models"
+	"my_blog/utils"
+	"time"
+)
+
+// trashPurgeBatch 每次永久删除的文章数量上限，避免单次清理占用数据库过久
+const trashPurgeBatch = 100
+
+// TrashService 回收站：列出、恢复已删除的文章和评论，并永久删除超过保留期的内容
+type TrashService struct {
+	media  *MediaService
+	logger *slog.Logger
+}
+
+// NewTrashService 创建回收站服务
+func NewTrashService(logger *slog.Logger) *TrashService {
+	return &TrashService{media: NewMediaService(logger), logger: logger}
+}
+
+// trashedInfo 根据删除时间和删除人生成删除信息，PurgeAt 按当前的保留期计算
+func trashedInfo(deletedAt time.Time, deletedBy sql.NullInt64) models.Trashed {
+	return models.Trashed{
+		DeletedAt: deletedAt,
+		DeletedBy: optionalID(deletedBy),
+		PurgeAt:   deletedAt.Add(config.TrashRetention),
+	}
+}
+
+// ListArticles 分页获取回收站中的文章，按删除时间倒序。all 为 true 时（管理员）返回所有文章，
+// 否则只返回 userID 作为作者的文章
+func (s *TrashService) ListArticles(userID int, all bool, limit, offset int) (*models.TrashedArticleList, error) {
+	const filter = `
+		FROM articles a
+		LEFT JOIN categories c ON a.category_id = c.id
+		WHERE a.deleted_at IS NOT NULL AND (? OR a.author_id = ?)`
+
+	list := &models.TrashedArticleList{Items: []models.TrashedArticle{}}
+	if err := config.DB.QueryRow("SELECT COUNT(*)"+filter, all, userID).Scan(&list.Total); err != nil {
+		return nil, err
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT `+articleColumns+`, a.deleted_at, a.deleted_by`+filter+`
+		ORDER BY a.deleted_at DESC, a.id DESC
+		LIMIT ? OFFSET ?
+	`, all, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.TrashedArticle
+		var deletedAt time.Time
+		var authorID, deletedBy sql.NullInt64
+		err := rows.Scan(
+			&item.ID,
+			&item.Author,
+			&authorID,
+			&item.Title,
+			&item.Content,
+			&item.CreateAt,
+			&item.ImagePath,
+			&item.Views,
+			&item.Category.ID,
+			&item.Category.Name,
+			&item.Category.Description,
+			&deletedAt,
+			&deletedBy,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.AuthorID = int(authorID.Int64)
+		setArticleImageVariants(&item.Article)
+		item.Trashed = trashedInfo(deletedAt, deletedBy)
+		list.Items = append(list.Items, item)
+	}
+	return list, rows.Err()
+}
+
+// ListComments 分页获取回收站中的评论，按删除时间倒序。all 为 true 时（管理员）返回所有评论，
+// 否则只返回 userID 发表的评论
+func (s *TrashService) ListComments(userID int, all bool, limit, offset int) (*models.TrashedCommentList, error) {
+	const filter = `
+		FROM comments c
+		WHERE c.deleted_at IS NOT NULL AND (? OR c.user_id = ?)`
+
+	list := &models.TrashedCommentList{Items: []models.TrashedComment{}}
+	if err := config.DB.QueryRow("SELECT COUNT(*)"+filter, all, userID).Scan(&list.Total); err != nil {
+		return nil, err
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT `+commentColumns+`, c.deleted_at, c.deleted_by`+filter+`
+		ORDER BY c.deleted_at DESC, c.id DESC
+		LIMIT ? OFFSET ?
+	`, all, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.TrashedComment
+		var authorID, parentID, deletedBy sql.NullInt64
+		var updatedAt sql.NullTime
+		var deletedAt time.Time
+		err := rows.Scan(
+			&item.ID,
+			&item.ArticleID,
+			&item.Content,
+			&item.Author,
+			&item.CreateAt,
+			&updatedAt,
+			&authorID,
+			&parentID,
+			&deletedAt,
+			&deletedBy,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.UpdatedAt = nullTime(updatedAt)
+		item.UserID = int(authorID.Int64)
+		item.ParentID = optionalID(parentID)
+		item.Trashed = trashedInfo(deletedAt, deletedBy)
+		list.Items = append(list.Items, item)
+	}
+	return list, rows.Err()
+}
+
+// RestoreArticle 从回收站恢复文章。管理员可以恢复任何文章，作者可以恢复不是由管理员删除的文章；
+// 对非作者隐藏回收站中的文章，返回 ErrArticleNotFound
+func (s *TrashService) RestoreArticle(id, userID int, admin bool) error {
+	var authorID, deletedBy sql.NullInt64
+	err := config.DB.QueryRow(
+		"SELECT author_id, deleted_by FROM articles WHERE id = ? AND deleted_at IS NOT NULL", id,
+	).Scan(&authorID, &deletedBy)
+	if err == sql.ErrNoRows {
+		return ErrArticleNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkRestore(userID, admin, authorID, deletedBy, ErrArticleNotFound); err != nil {
+		return err
+	}
+
+	return restore("articles", id, ErrArticleNotFound)
+}
+
+// RestoreComment 从回收站恢复评论，权限规则与 RestoreArticle 相同。
+// 所属文章或被回复的评论仍在回收站中时，评论在它们恢复后才可见
+func (s *TrashService) RestoreComment(id, userID int, admin bool) error {
+	var authorID, deletedBy sql.NullInt64
+	err := config.DB.QueryRow(
+		"SELECT user_id, deleted_by FROM comments WHERE id = ? AND deleted_at IS NOT NULL", id,
+	).Scan(&authorID, &deletedBy)
+	if err == sql.ErrNoRows {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkRestore(userID, admin, authorID, deletedBy, ErrCommentNotFound); err != nil {
+		return err
+	}
+
+	return restore("comments", id, ErrCommentNotFound)
+}
+
+// checkRestore 检查用户能否恢复作者为 authorID、由 deletedBy 删除的内容
+func checkRestore(userID int, admin bool, authorID, deletedBy sql.NullInt64, notFound error) error {
+	if admin {
+		return nil
+	}
+	if !authorID.Valid || int(authorID.Int64) != userID {
+		return notFound
+	}
+	if !deletedBy.Valid || int(deletedBy.Int64) == userID {
+		return nil
+	}
+
+	// 管理员删除的内容（如违规内容）只能由管理员恢复
+	var deleterRole int
+	err := config.DB.QueryRow("SELECT role_id FROM users WHERE id = ?", deletedBy.Int64).Scan(&deleterRole)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if deleterRole == utils.RoleAdmin {
+		return ErrRestoreNotAllowed
+	}
+	return nil
+}
+
+// restore 清除删除标记，并发恢复或已被永久删除时返回 notFound
+func restore(table string, id int, notFound error) error {
+	result, err := config.DB.Exec(
+		"UPDATE "+table+" SET deleted_at = NULL, deleted_by = NULL WHERE id = ? AND deleted_at IS NOT NULL", id,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// Purge 永久删除在回收站中超过 retention 的文章和评论，返回删除的文章数和评论数。
+// 文章的评论、反应、收藏等随文章级联删除，配图的引用随之释放
+func (s *TrashService) Purge(retention time.Duration) (articles, comments int, err error) {
+	cutoff := time.Now().Add(-retention)
+	for more := true; more; {
+		var n int
+		n, more, err = s.purgeArticles(cutoff)
+		articles += n
+		if err != nil {
+			return articles, comments, err
+		}
+	}
+
+	comments, err = purgeComments(cutoff)
+	return articles, comments, err
+}
+
+// purgeComments 永久删除在 cutoff 之前移入回收站的评论及其下的整个回复链。
+// 父评论移入回收站后回复已不可见，随父评论一起删除，不会作为脱离上下文的评论重新出现
+func purgeComments(cutoff time.Time) (int, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// 逐层将回复标记为与父评论同时删除，直到没有新的回复被标记
+	for {
+		result, err := tx.Exec(`
+			UPDATE comments c
+			JOIN comments p ON p.id = c.parent_id
+			SET c.deleted_at = p.deleted_at
+			WHERE p.deleted_at < ? AND (c.deleted_at IS NULL OR c.deleted_at >= ?)
+		`, cutoff, cutoff)
+		if err != nil {
+			return 0, err
+		}
+		marked, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if marked == 0 {
+			break
+		}
+	}
+
+	result, err := tx.Exec("DELETE FROM comments WHERE deleted_at < ?", cutoff)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), tx.Commit()
+}
+
+// purgeArticles 永久删除一批在 cutoff 之前移入回收站的文章，返回删除的数量以及是否可能还有未删除的文章
+func (s *TrashService) purgeArticles(cutoff time.Time) (purged int, more bool, err error) {
+	rows, err := config.DB.Query(
+		"SELECT id, image_path FROM articles WHERE deleted_at < ? ORDER BY deleted_at LIMIT ?", cutoff, trashPurgeBatch,
+	)
+	if err != nil {
+		return 0, false, err
+	}
+	type expired struct {
+		id        int
+		imagePath sql.NullString
+	}
+	var batch []expired
+	for rows.Next() {
+		var article expired
+		if err := rows.Scan(&article.id, &article.imagePath); err != nil {
+			rows.Close()
+			return 0, false, err
+		}
+		batch = append(batch, article)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, false, err
+	}
+
+	for _, article := range batch {
+		// 期间被恢复的文章不再删除
+		result, err := config.DB.Exec("DELETE FROM articles WHERE id = ? AND deleted_at < ?", article.id, cutoff)
+		if err != nil {
+			return purged, false, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return purged, false, err
+		}
+		if affected > 0 {
+			purged++
+			s.media.releaseUpload(article.imagePath.String)
+		}
+	}
+	return purged, len(batch) == trashPurgeBatch, nil
+}
+
+// RunPurger 按固定周期永久删除超过保留期的回收站内容，直到 ctx 取消
+func (s *TrashService) RunPurger(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			articles, comments, err := s.Purge(retention)
+			if err != nil {
+				s.logger.Error("trash purge failed", "error", err)
+				continue
+			}
+			if articles > 0 || comments > 0 {
+				s.logger.Info("trash purge finished", "articles", articles, "comments", comments)
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"database/sql"
+	"my_blog/config"
+	"my_blog/utils"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// useMockDB 将 config.DB 替换为 sqlmock，测试结束后恢复并检查所有预期的语句都已执行
+func useMockDB(t *testing.T) sqlmock.Sqlmock {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	old := config.DB
+	config.DB = db
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		config.DB = old
+		db.Close()
+	})
+	return mock
+}
+
+func TestCheckRestore(t *testing.T) {
+	const roleQuery = "SELECT role_id FROM users WHERE id = ?"
+	id := func(v int64) sql.NullInt64 { return sql.NullInt64{Int64: v, Valid: true} }
+
+	tests := []struct {
+		name        string
+		userID      int
+		admin       bool
+		authorID    sql.NullInt64
+		deletedBy   sql.NullInt64
+		deleterRole int // 不为0时预期查询删除人的角色
+		want        error
+	}{
+		{name: "admin restores anything", userID: 1, admin: true, authorID: id(2), deletedBy: id(3), want: nil},
+		{name: "author restores own deletion", userID: 2, authorID: id(2), deletedBy: id(2), want: nil},
+		{name: "deleter unknown", userID: 2, authorID: id(2), deletedBy: sql.NullInt64{}, want: nil},
+		{name: "deleted by admin", userID: 2, authorID: id(2), deletedBy: id(1), deleterRole: utils.RoleAdmin, want: ErrRestoreNotAllowed},
+		{name: "deleted by non-admin", userID: 2, authorID: id(2), deletedBy: id(5), deleterRole: utils.RoleUser, want: nil},
+		{name: "not the author", userID: 3, authorID: id(2), deletedBy: id(3), want: ErrCommentNotFound},
+		{name: "author account gone", userID: 3, authorID: sql.NullInt64{}, deletedBy: id(3), want: ErrCommentNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := useMockDB(t)
+			if tt.deleterRole != 0 {
+				mock.ExpectQuery(regexp.QuoteMeta(roleQuery)).WithArgs(tt.deletedBy.Int64).
+					WillReturnRows(sqlmock.NewRows([]string{"role_id"}).AddRow(tt.deleterRole))
+			}
+			err := checkRestore(tt.userID, tt.admin, tt.authorID, tt.deletedBy, ErrCommentNotFound)
+			if err != tt.want {
+				t.Errorf("checkRestore = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("deleter no longer exists", func(t *testing.T) {
+		mock := useMockDB(t)
+		mock.ExpectQuery(regexp.QuoteMeta(roleQuery)).WithArgs(9).
+			WillReturnRows(sqlmock.NewRows([]string{"role_id"}))
+		if err := checkRestore(2, false, id(2), id(9), ErrCommentNotFound); err != nil {
+			t.Errorf("checkRestore = %v, want nil", err)
+		}
+	})
+}
+
+func TestPurgeCommentsRemovesReplies(t *testing.T) {
+	const markReplies = `UPDATE comments c\s+JOIN comments p ON p.id = c.parent_id\s+` +
+		`SET c.deleted_at = p.deleted_at\s+WHERE p.deleted_at < \? AND \(c.deleted_at IS NULL OR c.deleted_at >= \?\)`
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("nested replies", func(t *testing.T) {
+		mock := useMockDB(t)
+		mock.ExpectBegin()
+		// 两层回复，第三次没有新的回复被标记
+		mock.ExpectExec(markReplies).WithArgs(cutoff, cutoff).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(markReplies).WithArgs(cutoff, cutoff).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(markReplies).WithArgs(cutoff, cutoff).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM comments WHERE deleted_at < ?")).
+			WithArgs(cutoff).WillReturnResult(sqlmock.NewResult(0, 4))
+		mock.ExpectCommit()
+
+		purged, err := purgeComments(cutoff)
+		if err != nil || purged != 4 {
+			t.Errorf("purgeComments = %d, %v; want 4, nil", purged, err)
+		}
+	})
+
+	t.Run("failure rolls back", func(t *testing.T) {
+		mock := useMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(markReplies).WithArgs(cutoff, cutoff).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM comments WHERE deleted_at < ?")).
+			WithArgs(cutoff).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		if _, err := purgeComments(cutoff); err != sql.ErrConnDone {
+			t.Errorf("purgeComments = %v, want ErrConnDone", err)
+		}
+	})
+}
+
+func TestDeleteArticleRequiresAuthorOrAdmin(t *testing.T) {
+	expectArticle := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT title, author_id FROM articles WHERE id = ?")).WithArgs(10).
+			WillReturnRows(sqlmock.NewRows([]string{"title", "author_id"}).AddRow("标题", 2))
+	}
+	s := &ArticleService{}
+
+	t.Run("other user", func(t *testing.T) {
+		mock := useMockDB(t)
+		expectArticle(mock)
+		if err := s.DeleteArticle(10, 3, false); err != ErrPermissionDenied {
+			t.Errorf("DeleteArticle = %v, want ErrPermissionDenied", err)
+		}
+	})
+
+	t.Run("author", func(t *testing.T) {
+		mock := useMockDB(t)
+		expectArticle(mock)
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE articles SET deleted_at = ?, deleted_by = ? WHERE id = ? AND deleted_at IS NULL")).
+			WithArgs(sqlmock.AnyArg(), 2, 10).WillReturnResult(sqlmock.NewResult(0, 1))
+		if err := s.DeleteArticle(10, 2, false); err != nil {
+			t.Errorf("DeleteArticle = %v, want nil", err)
+		}
+	})
+}
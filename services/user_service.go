@@ -2,14 +2,15 @@ package services
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"mime/multipart"
 	"my_blog/config"
+	"my_blog/events"
 	"my_blog/models"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -24,31 +25,41 @@ var allowedExtensions = map[string]bool{
 	".gif":  true,
 }
 
+// statusColumns users 表中账号状态的列，按 Status、StatusReason、StatusUntil 的顺序读取
+const statusColumns = "status, COALESCE(status_reason, ''), status_until"
+
 // UserService 用户服务
-type UserService struct{}
-
-// GenerateUniqueFileName 生成唯一文件名（时间戳+随机数）
-func GenerateUniqueFileName(originalName string) string {
-	ext := filepath.Ext(originalName)
-	timestamp := time.Now().UnixNano()
-	random := time.Now().Nanosecond() % 1000000
-	return fmt.Sprintf("%d_%06d%s", timestamp, random, ext)
+type UserService struct {
+	images        ImageService
+	media         *MediaService
+	notifications *NotificationService
+	logger        *slog.Logger
 }
 
-// SaveAvatar 保存文件到本地服务器
-func (s *UserService) SaveAvatar(file io.Reader, filename string) error {
-	uploadDir := "uploads"
-	if err := os.MkdirAll(uploadDir, 0750); err != nil {
-		return err
+// NewUserService 创建用户服务
+func NewUserService(logger *slog.Logger) *UserService {
+	return &UserService{
+		media:         NewMediaService(logger),
+		notifications: NewNotificationService(logger),
+		logger:        logger,
 	}
-	filePath := filepath.Join(uploadDir, filename)
-	out, err := os.Create(filePath)
-	if err != nil {
-		return err
+}
+
+// ValidateImageUpload 校验上传图片的扩展名和文件大小
+func ValidateImageUpload(fileHeader *multipart.FileHeader) error {
+	ext := filepath.Ext(fileHeader.Filename)
+	if !allowedExtensions[strings.ToLower(ext)] {
+		return ErrUnsupportedImage
 	}
-	defer out.Close()
-	_, err = io.Copy(out, file)
-	return err
+	if fileHeader.Size > maxUploadSize {
+		return ErrImageTooLarge
+	}
+	return nil
+}
+
+// SaveAvatar 处理头像并保存到本地服务器，返回 large 变体的访问路径
+func (s *UserService) SaveAvatar(file io.Reader) (string, error) {
+	return s.images.ProcessAndSave(file)
 }
 
 // Register 注册用户（存储路径）
@@ -61,24 +72,21 @@ func (s *UserService) Register(user *models.User, fileHeader *multipart.FileHead
 	user.Password = string(hashedPassword) // 更新为哈希后的密码
 	if fileHeader != nil {
 		// 验证文件类型和大小
-		ext := filepath.Ext(fileHeader.Filename)
-		if !allowedExtensions[strings.ToLower(ext)] || fileHeader.Size > 2*1024*1024 {
-			return errors.New("不支持的文件类型或文件过大")
+		if err := ValidateImageUpload(fileHeader); err != nil {
+			return err
 		}
 
-		// 生成唯一文件名
-		uniqueName := GenerateUniqueFileName(fileHeader.Filename)
-		user.ImageData = "/uploads/" + uniqueName // 存储相对路径
-
-		// 打开文件并保存
+		// 打开文件，生成多尺寸头像并保存
 		file, err := fileHeader.Open()
 		if err != nil {
 			return err
 		}
 		defer file.Close()
-		if err := s.SaveAvatar(file, uniqueName); err != nil {
+		imagePath, err := s.SaveAvatar(file)
+		if err != nil {
 			return err
 		}
+		user.ImageData = imagePath // 存储相对路径
 	}
 
 	// 插入数据库（仅存储路径）
@@ -86,56 +94,106 @@ func (s *UserService) Register(user *models.User, fileHeader *multipart.FileHead
 		INSERT INTO users (username, password, email, image_data) 
 		VALUES (?, ?, ?, ?)
 	`, user.Username, user.Password, user.Email, user.ImageData)
+	if isDuplicateEntry(err) {
+		return ErrUsernameTaken
+	}
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
 	if err != nil {
 		return err
 	}
-	_ = result // 若不需要 sql.Result，可忽略
+	user.ID = int(id)
+	s.media.acquireUpload(user.ImageData)
+	emit(events.UserRegistered, map[string]interface{}{
+		"id":       user.ID,
+		"username": user.Username,
+	})
 	return nil
 }
 
-// Login 用户登录
+// Login 用户登录，连续失败 config.MaxFailedLogins 次后账号锁定一段时间；
+// 已注销的账号视为不存在，暂停或封禁的账号在密码验证通过后拒绝登录
 func (s *UserService) Login(username, password string) (*models.User, error) {
 	var user models.User
 	var hashedPassword string
+	var failedLogins int
+	var lockedUntil, statusUntil sql.NullTime
 
 	err := config.DB.QueryRow(`
-		SELECT id, username, password, email
+		SELECT id, username, password, email, failed_logins, locked_until, status, status_until
 		FROM users WHERE username = ?
 	`, username).Scan(
 		&user.ID,
 		&user.Username,
 		&hashedPassword,
 		&user.Email,
+		&failedLogins,
+		&lockedUntil,
+		&user.Status,
+		&statusUntil,
 	)
 
-	if err == sql.ErrNoRows {
-		return nil, nil
+	if err == sql.ErrNoRows || user.Status == models.UserStatusDeleted {
+		return nil, ErrInvalidCredentials
 	}
 	if err != nil {
 		return nil, err
 	}
-	// 智能验证：判断密码是否为哈希格式
-	if strings.HasPrefix(hashedPassword, "$2a$") {
-		// 是哈希密码，使用 bcrypt 验证
-		if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)); err != nil {
-			return nil, err // 密码不匹配
-		}
-		// 验证成功，检查是否需要升级（可选）
-		if needUpgradeHash(hashedPassword) {
-			go s.upgradePassword(user.ID, password) // 异步升级密码
-		}
-	} else {
-		// 是明文密码，直接对比（仅针对老用户）
-		if hashedPassword != password {
-			return nil, errors.New("password mismatch")
-		}
-		// 明文验证成功，立即升级为哈希
-		go s.upgradePassword(user.ID, password) // 异步升级密码
+	if lockedUntil.Valid && lockedUntil.Time.After(time.Now()) {
+		return nil, ErrAccountLocked
+	}
+	if !passwordMatches(hashedPassword, password) {
+		return nil, s.loginFailed(user.ID, failedLogins)
+	}
+	// 只有密码正确时才提示账号被暂停或封禁，避免泄露账号状态
+	switch models.EffectiveStatus(user.Status, nullTime(statusUntil), time.Now()) {
+	case models.UserStatusSuspended:
+		return nil, ErrAccountSuspended
+	case models.UserStatusBanned:
+		return nil, ErrAccountBanned
+	}
+	// 明文密码或成本因子过低的哈希，验证成功后异步升级
+	if needUpgradeHash(hashedPassword) {
+		Go(func() { s.upgradePassword(user.ID, password) })
 	}
 
+	if failedLogins > 0 || lockedUntil.Valid {
+		if _, err := config.DB.Exec("UPDATE users SET failed_logins = 0, locked_until = NULL WHERE id = ?", user.ID); err != nil {
+			s.logger.Error("reset failed logins failed", "user_id", user.ID, "error", err)
+		}
+	}
 	return &user, nil
 }
 
+// loginFailed 记录一次登录失败，达到上限时锁定账号、重新计数并通知用户，返回 ErrInvalidCredentials
+func (s *UserService) loginFailed(userID, failedLogins int) error {
+	// MySQL 按顺序执行赋值，locked_until 需在 failed_logins 更新前根据旧值计算
+	_, err := config.DB.Exec(`
+		UPDATE users
+		SET locked_until = IF(failed_logins + 1 >= ?, ?, locked_until),
+			failed_logins = IF(failed_logins + 1 >= ?, 0, failed_logins + 1)
+		WHERE id = ?
+	`, config.MaxFailedLogins, time.Now().Add(config.LoginLockoutDuration), config.MaxFailedLogins, userID)
+	if err != nil {
+		s.logger.Error("record failed login failed", "user_id", userID, "error", err)
+		return ErrInvalidCredentials
+	}
+	if failedLogins+1 >= config.MaxFailedLogins {
+		s.notifications.AccountLocked(userID)
+	}
+	return ErrInvalidCredentials
+}
+
+// passwordMatches 智能验证：哈希密码使用 bcrypt 验证，明文密码直接对比（仅针对老用户）
+func passwordMatches(hashedPassword, password string) bool {
+	if strings.HasPrefix(hashedPassword, "$2a$") {
+		return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)) == nil
+	}
+	return hashedPassword == password
+}
+
 // 检查哈希是否需要升级（例如，成本因子变化时）
 func needUpgradeHash(hash string) bool {
 	cost, err := bcrypt.Cost([]byte(hash))
@@ -144,90 +202,324 @@ func needUpgradeHash(hash string) bool {
 func (s *UserService) upgradePassword(userId int, password string) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		log.Printf("Failed to hash password for user %d: %v", userId, err)
+		s.logger.Error("hash password failed", "user_id", userId, "error", err)
 		return
 	}
 	_, err = config.DB.Exec("UPDATE users SET password = ? WHERE id = ?", hashedPassword, userId)
 	if err != nil {
-		log.Printf("Failed to upgrade password for user %d: %v", userId, err)
+		s.logger.Error("upgrade password failed", "user_id", userId, "error", err)
 	}
 }
 
-// GetUserByID 根据ID获取用户信息
+// GetUserByID 根据ID获取用户信息，包含个人资料、关注数和粉丝数
 func (s *UserService) GetUserByID(id int) (*models.User, error) {
 	var user models.User
+	var links string
+	var statusUntil sql.NullTime
 	err := config.DB.QueryRow(`
-		SELECT id, username, email, image_data
+		SELECT id, username, email, image_data, background_image, role_id, COALESCE(locale, ''),
+			email_verified_at IS NOT NULL, `+statusColumns+`, `+profileColumns+`
 		FROM users WHERE id = ?
 	`, id).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
 		&user.ImageData,
+		&user.BackgroundImage,
+		&user.RoleID,
+		&user.Locale,
+		&user.EmailVerified,
+		&user.Status,
+		&user.StatusReason,
+		&statusUntil,
+		&user.Bio,
+		&user.Website,
+		&user.Location,
+		&links,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, ErrUserNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
+	user.StatusUntil = nullTime(statusUntil)
+	if user.SocialLinks, err = decodeSocialLinks(links); err != nil {
+		return nil, err
+	}
+
+	user.FollowerCount, user.FollowingCount, err = followCounts(user.ID)
+	if err != nil {
+		return nil, err
+	}
 
+	user.ImageVariants = ImageVariantsFor(user.ImageData)
+	user.BackgroundVariants = ImageVariantsFor(user.BackgroundImage)
 	return &user, nil
 }
 
-// UpdateUser 更新用户信息
-func (s *UserService) UpdateUser(id int, user *models.User) error {
-	if user.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+// UpdateUser 部分更新用户信息，只修改补丁中出现的字段。
+// 修改用户名时在同一事务中更新文章和评论上保存的作者名
+func (s *UserService) UpdateUser(id int, patch *models.UserPatch) error {
+	oldAvatar, _, err := s.userImages(id)
+	if err != nil {
+		return err
+	}
+
+	var set updateSet
+	set.addOptional("username", patch.Username)
+	if patch.Email.Set {
+		// 邮箱变更后需重新验证；MySQL 按顺序执行赋值，须在更新 email 前比较
+		set.add("email_verified_at = IF(email = ?, email_verified_at, NULL), email = ?", patch.Email.Value, patch.Email.Value)
+	}
+	if patch.Password.Set {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(patch.Password.Value), bcrypt.DefaultCost)
 		if err != nil {
 			return err
 		}
-		user.Password = string(hashedPassword)
+		set.add("password = ?, failed_logins = 0, locked_until = NULL", string(hashedPassword))
+	}
+	set.addOptional("image_data", patch.ImageData)
+	set.addOptional("locale", patch.Locale)
+	set.addOptional("bio", patch.Bio)
+	set.addOptional("website", patch.Website)
+	set.addOptional("location", patch.Location)
+	if patch.SocialLinks.Set {
+		links, err := s.patchedSocialLinks(id, patch.SocialLinks)
+		if err != nil {
+			return err
+		}
+		set.add("social_links = ?", links)
+	}
+	if set.empty() {
+		return nil
 	}
 
-	_, err := config.DB.Exec(`
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("UPDATE users SET "+set.clause()+" WHERE id = ?", append(set.args, id)...)
+	if isDuplicateEntry(err) {
+		return ErrUsernameTaken
+	}
+	if err != nil {
+		return err
+	}
+	if patch.Username.Set {
+		if _, err := tx.Exec("UPDATE articles SET author = ? WHERE author_id = ?", patch.Username.Value, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("UPDATE comments SET author = ? WHERE user_id = ?", patch.Username.Value, id); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if patch.ImageData.Set {
+		s.media.replaceUpload(oldAvatar, patch.ImageData.Value)
+	}
+	return nil
+}
+
+// patchedSocialLinks 将社交链接的部分更新合并到当前链接，返回要保存的JSON，整体为 null 时返回nil
+func (s *UserService) patchedSocialLinks(id int, patch models.Optional[models.SocialLinksPatch]) (interface{}, error) {
+	if patch.Null {
+		return nil, nil
+	}
+
+	var raw string
+	err := config.DB.QueryRow("SELECT COALESCE(social_links, '') FROM users WHERE id = ?", id).Scan(&raw)
+	if err != nil {
+		return nil, err
+	}
+	current, err := decodeSocialLinks(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := json.Marshal(mergeSocialLinks(current, patch.Value))
+	if err != nil {
+		return nil, err
+	}
+	return string(links), nil
+}
+
+// UpdateProfile 更新个人主页资料，未填写的字段会被清空
+func (s *UserService) UpdateProfile(id int, profile *models.Profile) error {
+	links, err := json.Marshal(profile.SocialLinks)
+	if err != nil {
+		return err
+	}
+
+	_, err = config.DB.Exec(`
 		UPDATE users
-		SET username = ?, email = ?, password = ?, image_data = ?
+		SET bio = ?, website = ?, location = ?, social_links = ?
 		WHERE id = ?
-	`,
-		user.Username,
-		user.Email,
-		user.Password,
-		user.ImageData,
-		id,
-	)
+	`, profile.Bio, profile.Website, profile.Location, string(links), id)
 	return err
 }
 
-// DeleteUser 删除用户
+// userImages 获取用户当前的头像和背景图路径
+func (s *UserService) userImages(id int) (avatar, background string, err error) {
+	err = config.DB.QueryRow(`
+		SELECT COALESCE(image_data, ''), COALESCE(background_image, '')
+		FROM users WHERE id = ?
+	`, id).Scan(&avatar, &background)
+	if err == sql.ErrNoRows {
+		return "", "", ErrUserNotFound
+	}
+	return avatar, background, err
+}
+
+// DeleteUser 注销用户：保留用户记录以维持文章和评论的关联，但清除个人资料并匿名化用户名，
+// 同时删除关注、收藏、阅读列表、通知和令牌等私人数据
 func (s *UserService) DeleteUser(id int) error {
-	result, err := config.DB.Exec("DELETE FROM users WHERE id = ?", id)
+	tx, err := config.DB.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	affected, err := result.RowsAffected()
+	var status, avatar, background string
+	err = tx.QueryRow(`
+		SELECT status, COALESCE(image_data, ''), COALESCE(background_image, '')
+		FROM users WHERE id = ? FOR UPDATE
+	`, id).Scan(&status, &avatar, &background)
+	if err == sql.ErrNoRows || status == models.UserStatusDeleted {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	anonymous := deletedUsername(id)
+	_, err = tx.Exec(`
+		UPDATE users
+		SET username = ?, email = '', password = '',
+			image_data = DEFAULT(image_data), background_image = DEFAULT(background_image),
+			locale = NULL, email_verified_at = NULL, failed_logins = 0, locked_until = NULL,
+			bio = NULL, website = NULL, location = NULL, social_links = NULL,
+			status = ?, status_reason = NULL, status_until = NULL, deleted_at = ?
+		WHERE id = ?
+	`, anonymous, models.UserStatusDeleted, time.Now(), id)
 	if err != nil {
 		return err
 	}
 
-	if affected == 0 {
-		return sql.ErrNoRows
+	// 文章和评论保存了作者用户名用于展示，改为匿名后的用户名
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{"UPDATE articles SET author = ? WHERE author_id = ?", []interface{}{anonymous, id}},
+		{"UPDATE comments SET author = ? WHERE user_id = ?", []interface{}{anonymous, id}},
+		{"DELETE FROM user_follows WHERE follower_id = ? OR followee_id = ?", []interface{}{id, id}},
+		{"DELETE FROM category_follows WHERE user_id = ?", []interface{}{id}},
+		{"DELETE FROM bookmarks WHERE user_id = ?", []interface{}{id}},
+		{"DELETE FROM reading_lists WHERE user_id = ?", []interface{}{id}},
+		{"DELETE FROM notifications WHERE user_id = ?", []interface{}{id}},
+		{"DELETE FROM notification_preferences WHERE user_id = ?", []interface{}{id}},
+		{"DELETE FROM user_tokens WHERE user_id = ?", []interface{}{id}},
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt.query, stmt.args...); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.media.releaseUpload(avatar)
+	s.media.releaseUpload(background)
+	return nil
+}
+
+// nullTime 将可为NULL的时间转换为指针
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// usernameOf 获取用户当前的用户名
+func usernameOf(id int) (string, error) {
+	var username string
+	err := config.DB.QueryRow("SELECT username FROM users WHERE id = ?", id).Scan(&username)
+	if err == sql.ErrNoRows {
+		return "", ErrUserNotFound
+	}
+	return username, err
+}
+
+// deletedUsername 注销后的匿名用户名，包含注册时不允许的字符，不会与其他用户冲突
+func deletedUsername(id int) string {
+	return fmt.Sprintf("deleted-user-%d", id)
+}
+
+// UpdateUserStatus 管理员修改账号状态并通知用户，不能修改自己的状态，也不能修改已注销的账号
+func (s *UserService) UpdateUserStatus(userID, actorID int, change *models.UserStatusChange) error {
+	if userID == actorID {
+		return ErrCannotChangeOwnStatus
+	}
+	if change.Until != nil && (change.Status != models.UserStatusSuspended || !change.Until.After(time.Now())) {
+		return ErrInvalidStatusUntil
 	}
 
+	var status string
+	err := config.DB.QueryRow("SELECT status FROM users WHERE id = ?", userID).Scan(&status)
+	if err == sql.ErrNoRows || status == models.UserStatusDeleted {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	// 恢复正常时清除原因和截止时间
+	var reason interface{}
+	if change.Status == models.UserStatusActive {
+		change.Reason, change.Until = "", nil
+	} else if change.Reason != "" {
+		reason = change.Reason
+	}
+	_, err = config.DB.Exec(
+		"UPDATE users SET status = ?, status_reason = ?, status_until = ? WHERE id = ?",
+		change.Status, reason, change.Until, userID,
+	)
+	if err != nil {
+		return err
+	}
+	s.notifications.StatusChanged(userID, change)
 	return nil
 }
 
-// UpdateUserRole 更新用户角色
+// UpdateUserRole 更新用户角色并通知用户
 func (s *UserService) UpdateUserRole(userID, roleID int) error {
-	_, err := config.DB.Exec("UPDATE users SET role_id = ? WHERE id = ?", roleID, userID)
-	return err
+	if _, err := s.GetUserByID(userID); err != nil {
+		return err
+	}
+
+	if _, err := config.DB.Exec("UPDATE users SET role_id = ? WHERE id = ?", roleID, userID); err != nil {
+		return err
+	}
+	s.notifications.RoleChanged(userID, roleID)
+	return nil
 }
 
-// GetAllUsers 获取所有用户
-func (s *UserService) GetAllUsers() ([]models.User, error) {
-	rows, err := config.DB.Query("SELECT id, username, email, image_data FROM users")
+// GetAllUsers 获取所有用户，status 不为空时只返回该状态的用户
+func (s *UserService) GetAllUsers(status string) ([]models.User, error) {
+	rows, err := config.DB.Query(`
+		SELECT id, username, email, image_data, background_image, role_id, email_verified_at IS NOT NULL, `+statusColumns+`
+		FROM users
+		WHERE ? = '' OR status = ?
+	`, status, status)
 	if err != nil {
 		return nil, err
 	}
@@ -236,42 +528,51 @@ func (s *UserService) GetAllUsers() ([]models.User, error) {
 	var users []models.User
 	for rows.Next() {
 		var user models.User
+		var statusUntil sql.NullTime
 		err := rows.Scan(
 			&user.ID,
 			&user.Username,
 			&user.Email,
 			&user.ImageData,
+			&user.BackgroundImage,
+			&user.RoleID,
+			&user.EmailVerified,
+			&user.Status,
+			&user.StatusReason,
+			&statusUntil,
 		)
 		if err != nil {
 			return nil, err
 		}
+		user.StatusUntil = nullTime(statusUntil)
+		user.ImageVariants = ImageVariantsFor(user.ImageData)
+		user.BackgroundVariants = ImageVariantsFor(user.BackgroundImage)
 		users = append(users, user)
 	}
 	return users, nil
 }
 
-// SaveBackgroundImage 保存背景图到本地服务器
-func (s *UserService) SaveBackgroundImage(file io.Reader, filename string) error {
-	uploadDir := "uploads/backgrounds"
-	if err := os.MkdirAll(uploadDir, 0750); err != nil {
-		return err
-	}
-	filePath := filepath.Join(uploadDir, filename)
-	out, err := os.Create(filePath)
-	if err != nil {
-		return err
-	}
-	defer out.Close()
-	_, err = io.Copy(out, file)
-	return err
+// SaveBackgroundImage 处理背景图并保存到本地服务器，返回 large 变体的访问路径
+func (s *UserService) SaveBackgroundImage(file io.Reader) (string, error) {
+	return s.images.ProcessAndSave(file)
 }
 
 // UpdateUserBackgroundImage 更新用户背景图
 func (s *UserService) UpdateUserBackgroundImage(id int, backgroundImage string) error {
-	_, err := config.DB.Exec(`
+	_, oldBackground, err := s.userImages(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = config.DB.Exec(`
         UPDATE users
         SET background_image = ?
         WHERE id = ?
     `, backgroundImage, id)
-	return err
+	if err != nil {
+		return err
+	}
+
+	s.media.replaceUpload(oldBackground, backgroundImage)
+	return nil
 }
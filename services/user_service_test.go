@@ -0,0 +1,61 @@
+package services
+
+import (
+	"my_blog/models"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestUpdateUserRenamesAuthor(t *testing.T) {
+	expectImages := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT COALESCE\\(image_data, ''\\), COALESCE\\(background_image, ''\\)\\s+FROM users WHERE id = \\?").WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"image_data", "background_image"}).AddRow("", ""))
+	}
+	patch := &models.UserPatch{Username: models.Some("alice2")}
+
+	t.Run("renamed", func(t *testing.T) {
+		mock := useMockDB(t)
+		expectImages(mock)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET username = ? WHERE id = ?")).WithArgs("alice2", 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE articles SET author = ? WHERE author_id = ?")).WithArgs("alice2", 4).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE comments SET author = ? WHERE user_id = ?")).WithArgs("alice2", 4).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectCommit()
+
+		if err := (&UserService{}).UpdateUser(4, patch); err != nil {
+			t.Errorf("UpdateUser = %v", err)
+		}
+	})
+
+	t.Run("name taken", func(t *testing.T) {
+		mock := useMockDB(t)
+		expectImages(mock)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET username = ? WHERE id = ?")).WithArgs("alice2", 4).
+			WillReturnError(&mysql.MySQLError{Number: mysqlDuplicateEntry})
+		mock.ExpectRollback()
+
+		if err := (&UserService{}).UpdateUser(4, patch); err != ErrUsernameTaken {
+			t.Errorf("UpdateUser = %v, want ErrUsernameTaken", err)
+		}
+	})
+
+	t.Run("other fields only", func(t *testing.T) {
+		mock := useMockDB(t)
+		expectImages(mock)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET bio = ? WHERE id = ?")).WithArgs("hi", 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		if err := (&UserService{}).UpdateUser(4, &models.UserPatch{Bio: models.Some("hi")}); err != nil {
+			t.Errorf("UpdateUser = %v", err)
+		}
+	})
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"my_blog/config"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 投递请求头。签名为 HMAC-SHA256(secret, 时间戳 + "." + 请求体) 的十六进制，
+// 接收方应校验签名并拒绝时间戳过旧的请求以防重放
+const (
+	webhookEventHeader     = "X-Webhook-Event"
+	webhookDeliveryHeader  = "X-Webhook-Delivery"
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+	webhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// maxWebhookRetryDelay 重试间隔上限
+const maxWebhookRetryDelay = 6 * time.Hour
+
+// maxWebhookErrorBody 失败时记录的响应体最大字节数
+const maxWebhookErrorBody = 512
+
+// WebhookDispatcher Webhook 投递队列的后台处理，非2xx响应或请求失败时按指数退避重试，
+// 超过 config.WebhookMaxAttempts 次后标记为失败。停用的 Webhook 的投递会保留在队列中，重新启用后继续投递
+type WebhookDispatcher struct {
+	client *http.Client
+	logger *slog.Logger
+}
+
+// NewWebhookDispatcher 创建 Webhook 投递处理器
+func NewWebhookDispatcher(logger *slog.Logger) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		client: &http.Client{
+			Timeout: config.WebhookTimeout,
+			// 不跟随重定向：签名只针对配置的地址，且重定向可能指向内网地址
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: logger,
+	}
+}
+
+// queuedDelivery 队列中待投递的事件
+type queuedDelivery struct {
+	id            int64
+	event         string
+	payload       []byte
+	attempts      int
+	nextAttemptAt time.Time
+	url           string
+	secret        string
+}
+
+// Run 按固定周期投递到期的事件，并每 config.WebhookPruneInterval 清理一次过期的投递记录，直到 ctx 取消
+func (d *WebhookDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	prune := time.NewTicker(config.WebhookPruneInterval)
+	defer prune.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.ProcessBatch(ctx, config.WebhookBatchSize); err != nil && ctx.Err() == nil {
+				d.logger.Error("process webhook queue failed", "error", err)
+			}
+		case <-prune.C:
+			pruned, err := d.PruneDeliveries(config.WebhookDeliveryRetention)
+			if err != nil {
+				d.logger.Error("prune webhook deliveries failed", "error", err)
+				continue
+			}
+			if pruned > 0 {
+				d.logger.Info("pruned webhook deliveries", "count", pruned)
+			}
+		}
+	}
+}
+
+// PruneDeliveries 删除创建时间超过 retention 的已完成投递记录，返回删除的数量。待投递的记录不删除
+func (d *WebhookDispatcher) PruneDeliveries(retention time.Duration) (int, error) {
+	result, err := config.DB.Exec(
+		"DELETE FROM webhook_deliveries WHERE status IN (?, ?) AND created_at < ?",
+		deliverySuccess, deliveryFailed, time.Now().Add(-retention),
+	)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+// ProcessBatch 投递最多 limit 个到期的事件，返回投递成功的数量。
+// ctx 取消时中断进行中的请求并不再领取后续事件，返回 ctx 的错误
+func (d *WebhookDispatcher) ProcessBatch(ctx context.Context, limit int) (int, error) {
+	rows, err := config.DB.Query(`
+		SELECT d.id, d.event, d.payload, d.attempts, d.next_attempt_at, w.url, w.secret
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = ? AND d.next_attempt_at <= ? AND w.active = TRUE
+		ORDER BY d.next_attempt_at
+		LIMIT ?
+	`, deliveryPending, time.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var batch []queuedDelivery
+	for rows.Next() {
+		var q queuedDelivery
+		if err := rows.Scan(&q.id, &q.event, &q.payload, &q.attempts, &q.nextAttemptAt, &q.url, &q.secret); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batch = append(batch, q)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, q := range batch {
+		if err := ctx.Err(); err != nil {
+			return delivered, err
+		}
+		claimed, err := d.claim(q)
+		if err != nil {
+			return delivered, err
+		}
+		if !claimed {
+			continue // 已被其他实例领取
+		}
+		if d.deliver(ctx, q) {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// claim 以租约方式领取投递，做法与 MailQueue.claim 相同
+func (d *WebhookDispatcher) claim(q queuedDelivery) (bool, error) {
+	result, err := config.DB.Exec(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id = ? AND status = ? AND next_attempt_at = ?
+	`, time.Now().Add(config.WebhookClaimTimeout), q.id, deliveryPending, q.nextAttemptAt)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// deliver 发送事件并记录结果，返回是否投递成功
+func (d *WebhookDispatcher) deliver(ctx context.Context, q queuedDelivery) bool {
+	status, sendErr := d.send(ctx, q)
+	attempts := q.attempts + 1
+	now := time.Now()
+
+	if sendErr != nil && ctx.Err() != nil {
+		// 服务关闭中断了投递，不计入尝试次数，释放租约以便重启后立即重新投递
+		_, err := config.DB.Exec(
+			"UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ? AND status = ?", now, q.id, deliveryPending,
+		)
+		if err != nil {
+			d.logger.Error("release webhook delivery failed", "delivery_id", q.id, "error", err)
+		}
+		return false
+	}
+
+	var responseStatus interface{}
+	if status != 0 {
+		responseStatus = status
+	}
+
+	var err error
+	switch {
+	case sendErr == nil:
+		_, err = config.DB.Exec(`
+			UPDATE webhook_deliveries
+			SET status = ?, attempts = ?, response_status = ?, delivered_at = ?, last_error = NULL
+			WHERE id = ?
+		`, deliverySuccess, attempts, responseStatus, now, q.id)
+	case attempts >= config.WebhookMaxAttempts:
+		d.logger.Error("webhook delivery failed permanently", "delivery_id", q.id, "attempts", attempts, "error", sendErr)
+		_, err = config.DB.Exec(`
+			UPDATE webhook_deliveries
+			SET status = ?, attempts = ?, response_status = ?, last_error = ?
+			WHERE id = ?
+		`, deliveryFailed, attempts, responseStatus, sendErr.Error(), q.id)
+	default:
+		delay := retryDelay(config.WebhookRetryBackoff, maxWebhookRetryDelay, attempts)
+		d.logger.Warn("webhook delivery failed, will retry", "delivery_id", q.id, "attempts", attempts, "retry_in", delay, "error", sendErr)
+		_, err = config.DB.Exec(`
+			UPDATE webhook_deliveries
+			SET attempts = ?, response_status = ?, next_attempt_at = ?, last_error = ?
+			WHERE id = ?
+		`, attempts, responseStatus, now.Add(delay), sendErr.Error(), q.id)
+	}
+	if err != nil {
+		d.logger.Error("update webhook delivery failed", "delivery_id", q.id, "error", err)
+	}
+	return sendErr == nil
+}
+
+// send 发送签名的投递请求，返回响应状态码（请求未完成时为0）
+func (d *WebhookDispatcher) send(ctx context.Context, q queuedDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, q.url, bytes.NewReader(q.payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "my_blog-webhook")
+	req.Header.Set(webhookEventHeader, q.event)
+	req.Header.Set(webhookDeliveryHeader, strconv.FormatInt(q.id, 10))
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, "sha256="+signWebhook(q.secret, timestamp, q.payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookErrorBody))
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	// 读完响应体以便复用连接
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookErrorBody))
+	return resp.StatusCode, nil
+}
+
+// signWebhook 计算投递请求的签名
+func signWebhook(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
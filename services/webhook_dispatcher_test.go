@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestWebhookDispatcherDoesNotFollowRedirects(t *testing.T) {
+	followed := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		followed = true
+	}))
+	defer internal.Close()
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL, http.StatusFound)
+	}))
+	defer receiver.Close()
+
+	d := NewWebhookDispatcher(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	status, err := d.send(context.Background(), queuedDelivery{id: 1, event: "article.created", payload: []byte(`{}`), url: receiver.URL, secret: "s"})
+	if status != http.StatusFound || err == nil {
+		t.Errorf("send = %d, %v; want 302 and an error", status, err)
+	}
+	if followed {
+		t.Error("redirect was followed")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		base, limit time.Duration
+		attempts    int
+		want        time.Duration
+	}{
+		{time.Minute, time.Hour, 0, time.Minute},
+		{time.Minute, time.Hour, 1, time.Minute},
+		{time.Minute, time.Hour, 2, 2 * time.Minute},
+		{time.Minute, time.Hour, 4, 8 * time.Minute},
+		{time.Minute, time.Hour, 6, 32 * time.Minute},
+		{time.Minute, time.Hour, 7, time.Hour},
+		// 次数很大时不会溢出
+		{time.Minute, time.Hour, 1000, time.Hour},
+		{2 * time.Hour, time.Hour, 1, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.base, tt.limit, tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%v, %v, %d) = %v, want %v", tt.base, tt.limit, tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	tests := []struct {
+		secret, timestamp, payload string
+		want                       string
+	}{
+		// HMAC-SHA256(secret, timestamp + "." + payload)
+		{"whsec_test", "1700000000", `{"event":"article.created"}`, "3d76d5c1420024574e42f1b2479d00167e04839bcc3e49ae2e72679ae466f42c"},
+		{"", "0", "", "b849d5a581847b281957065739df36df2463d1977ea8d6e1e4e6cf33fadc68c3"},
+	}
+	for _, tt := range tests {
+		if got := signWebhook(tt.secret, tt.timestamp, []byte(tt.payload)); got != tt.want {
+			t.Errorf("signWebhook(%q, %q, %q) = %s, want %s", tt.secret, tt.timestamp, tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookDispatcherSignsRequests(t *testing.T) {
+	payload := []byte(`{"id":1}`)
+	var header http.Header
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer receiver.Close()
+
+	d := NewWebhookDispatcher(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	status, err := d.send(context.Background(), queuedDelivery{id: 42, event: "comment.created", payload: payload, url: receiver.URL, secret: "s3cret"})
+	if status != http.StatusOK || err != nil {
+		t.Fatalf("send = %d, %v", status, err)
+	}
+
+	timestamp := header.Get(webhookTimestampHeader)
+	if _, err := strconv.ParseInt(timestamp, 10, 64); err != nil {
+		t.Fatalf("timestamp header = %q", timestamp)
+	}
+	if got, want := header.Get(webhookSignatureHeader), "sha256="+signWebhook("s3cret", timestamp, payload); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	if got := header.Get(webhookEventHeader); got != "comment.created" {
+		t.Errorf("event header = %q", got)
+	}
+	if got := header.Get(webhookDeliveryHeader); got != "42" {
+		t.Errorf("delivery header = %q", got)
+	}
+	if string(body) != string(payload) {
+		t.Errorf("body = %s", body)
+	}
+}
+
+// expectDueDeliveries 为 ProcessBatch 准备到期投递的查询结果
+func expectDueDeliveries(mock sqlmock.Sqlmock, url string, due time.Time, ids ...int64) {
+	rows := sqlmock.NewRows([]string{"id", "event", "payload", "attempts", "next_attempt_at", "url", "secret"})
+	for _, id := range ids {
+		rows.AddRow(id, "article.created", []byte(`{}`), 0, due, url, "s")
+	}
+	mock.ExpectQuery("SELECT d.id, d.event, (.+) FROM webhook_deliveries d").WillReturnRows(rows)
+}
+
+func TestProcessBatchStopsWhenCancelled(t *testing.T) {
+	mock := useMockDB(t)
+	expectDueDeliveries(mock, "http://127.0.0.1:1", time.Now(), 1, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d := NewWebhookDispatcher(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if n, err := d.ProcessBatch(ctx, 10); n != 0 || err != context.Canceled {
+		t.Errorf("ProcessBatch = %d, %v; want 0, context.Canceled", n, err)
+	}
+}
+
+func TestProcessBatchReleasesInterruptedDelivery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 模拟响应缓慢的接收方，投递过程中服务开始关闭
+		cancel()
+		<-done
+	}))
+	defer receiver.Close()
+	defer close(done)
+
+	mock := useMockDB(t)
+	due := time.Now().Add(-time.Minute)
+	expectDueDeliveries(mock, receiver.URL, due, 1, 2)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE webhook_deliveries SET next_attempt_at = ?")).
+		WithArgs(sqlmock.AnyArg(), 1, deliveryPending, due).WillReturnResult(sqlmock.NewResult(0, 1))
+	// 不计入尝试次数，只释放租约；第二个投递不再领取
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ? AND status = ?")).
+		WithArgs(sqlmock.AnyArg(), 1, deliveryPending).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	d := NewWebhookDispatcher(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if n, err := d.ProcessBatch(ctx, 10); n != 0 || err != context.Canceled {
+		t.Errorf("ProcessBatch = %d, %v; want 0, context.Canceled", n, err)
+	}
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"my_blog/config"
+	"my_blog/events"
+	"my_blog/models"
+	"strings"
+	"time"
+)
+
+// Webhook 投递状态
+const (
+	deliveryPending = "pending"
+	deliverySuccess = "success"
+	deliveryFailed  = "failed"
+)
+
+// webhookSecretBytes 自动生成的签名密钥长度
+const webhookSecretBytes = 32
+
+// WebhookService 管理 Webhook 订阅，并将领域事件写入投递队列，由 WebhookDispatcher 投递
+type WebhookService struct {
+	logger *slog.Logger
+}
+
+// NewWebhookService 创建 Webhook 服务
+func NewWebhookService(logger *slog.Logger) *WebhookService {
+	return &WebhookService{logger: logger}
+}
+
+// webhookPayload 投递给订阅方的JSON请求体
+type webhookPayload struct {
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// Enqueue 为订阅了该事件的所有启用的 Webhook 创建投递记录，作为事件总线的处理函数
+func (s *WebhookService) Enqueue(e events.Event) {
+	hooks, err := s.subscribers(e.Type)
+	if err != nil {
+		s.logger.Error("load webhooks failed", "event", e.Type, "error", err)
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(webhookPayload{Event: e.Type, OccurredAt: e.OccurredAt, Data: e.Data})
+	if err != nil {
+		s.logger.Error("encode webhook payload failed", "event", e.Type, "error", err)
+		return
+	}
+
+	now := time.Now()
+	for _, id := range hooks {
+		_, err := config.DB.Exec(`
+			INSERT INTO webhook_deliveries (webhook_id, event, payload, status, attempts, next_attempt_at, created_at)
+			VALUES (?, ?, ?, ?, 0, ?, ?)
+		`, id, e.Type, payload, deliveryPending, now, now)
+		if err != nil {
+			s.logger.Error("enqueue webhook delivery failed", "webhook_id", id, "event", e.Type, "error", err)
+		}
+	}
+}
+
+// subscribers 返回订阅了 eventType 的启用中的 Webhook ID
+func (s *WebhookService) subscribers(eventType string) ([]int, error) {
+	rows, err := config.DB.Query("SELECT id, events FROM webhooks WHERE active = TRUE")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		var subscribed string
+		if err := rows.Scan(&id, &subscribed); err != nil {
+			return nil, err
+		}
+		for _, t := range strings.Split(subscribed, ",") {
+			if t == eventType {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	return ids, rows.Err()
+}
+
+// GetWebhooks 获取所有 Webhook，不包含签名密钥
+func (s *WebhookService) GetWebhooks() ([]models.Webhook, error) {
+	rows, err := config.DB.Query("SELECT id, url, events, active, created_at FROM webhooks ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hooks := []models.Webhook{}
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, rows.Err()
+}
+
+// GetWebhookByID 根据ID获取 Webhook，不包含签名密钥
+func (s *WebhookService) GetWebhookByID(id int) (*models.Webhook, error) {
+	hook, err := scanWebhook(config.DB.QueryRow(
+		"SELECT id, url, events, active, created_at FROM webhooks WHERE id = ?", id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// scanWebhook 按 id, url, events, active, created_at 的顺序读取 Webhook
+func scanWebhook(row rowScanner) (models.Webhook, error) {
+	var hook models.Webhook
+	var subscribed string
+	err := row.Scan(&hook.ID, &hook.URL, &subscribed, &hook.Active, &hook.CreatedAt)
+	hook.Events = strings.Split(subscribed, ",")
+	return hook, err
+}
+
+// CreateWebhook 创建 Webhook，未指定签名密钥时自动生成
+func (s *WebhookService) CreateWebhook(hook *models.Webhook) error {
+	if err := validateEvents(hook.Events); err != nil {
+		return err
+	}
+	if hook.Secret == "" {
+		raw := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return err
+		}
+		hook.Secret = hex.EncodeToString(raw)
+	}
+
+	hook.CreatedAt = time.Now()
+	result, err := config.DB.Exec(`
+		INSERT INTO webhooks (url, secret, events, active, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, hook.URL, hook.Secret, strings.Join(hook.Events, ","), hook.Active, hook.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	hook.ID = int(id)
+	return nil
+}
+
+// UpdateWebhook 更新 Webhook 的地址、订阅事件和启用状态，secret 不为空时同时更换签名密钥
+func (s *WebhookService) UpdateWebhook(id int, hook *models.Webhook) error {
+	if err := validateEvents(hook.Events); err != nil {
+		return err
+	}
+	if _, err := s.GetWebhookByID(id); err != nil {
+		return err
+	}
+
+	_, err := config.DB.Exec(`
+		UPDATE webhooks
+		SET url = ?, events = ?, active = ?, secret = COALESCE(NULLIF(?, ''), secret)
+		WHERE id = ?
+	`, hook.URL, strings.Join(hook.Events, ","), hook.Active, hook.Secret, id)
+	return err
+}
+
+// DeleteWebhook 删除 Webhook 及其投递记录
+func (s *WebhookService) DeleteWebhook(id int) error {
+	result, err := config.DB.Exec("DELETE FROM webhooks WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// GetDeliveries 获取 Webhook 的投递记录，按时间倒序
+func (s *WebhookService) GetDeliveries(webhookID, limit, offset int) ([]models.WebhookDelivery, error) {
+	if _, err := s.GetWebhookByID(webhookID); err != nil {
+		return nil, err
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT id, webhook_id, event, payload, status, attempts, response_status,
+			COALESCE(last_error, ''), next_attempt_at, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE webhook_id = ?
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`, webhookID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		var payload []byte
+		var responseStatus sql.NullInt64
+		var deliveredAt sql.NullTime
+		err := rows.Scan(
+			&d.ID,
+			&d.WebhookID,
+			&d.Event,
+			&payload,
+			&d.Status,
+			&d.Attempts,
+			&responseStatus,
+			&d.LastError,
+			&d.NextAttemptAt,
+			&d.CreatedAt,
+			&deliveredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		d.Payload = payload
+		if responseStatus.Valid {
+			status := int(responseStatus.Int64)
+			d.ResponseStatus = &status
+		}
+		if deliveredAt.Valid {
+			d.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// Redeliver 将投递记录重新加入队列并清零尝试次数，请求体保持不变，签名在投递时重新生成
+func (s *WebhookService) Redeliver(webhookID int, deliveryID int64) error {
+	var exists bool
+	err := config.DB.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM webhook_deliveries WHERE id = ? AND webhook_id = ?)", deliveryID, webhookID,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrDeliveryNotFound
+	}
+
+	_, err = config.DB.Exec(`
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = 0, next_attempt_at = ?
+		WHERE id = ?
+	`, deliveryPending, time.Now(), deliveryID)
+	return err
+}
+
+// validateEvents 订阅的事件不能为空，且必须是已定义的事件类型
+func validateEvents(types []string) error {
+	if len(types) == 0 {
+		return ErrUnknownWebhookEvent
+	}
+	for _, t := range types {
+		if !events.Known(t) {
+			return ErrUnknownWebhookEvent
+		}
+	}
+	return nil
+}
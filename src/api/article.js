@@ -34,4 +34,18 @@ export const getCommentsByArticleId = (id) => {
 // 创建评论
 export const createComment = (articleId, data) => {
     return axios.post(`/articles/${articleId}/comments`, data);
-};
\ No newline at end of file
+};
+// 切换对文章的反应（like、love、laugh、wow、sad、celebrate）
+export const toggleArticleReaction = (id, reaction) => {
+    return axios.post(`/articles/${id}/reactions`, { reaction });
+};
+
+// 回收站中的文章（作者只能看到自己的文章，管理员可以看到全部）
+export const getTrashedArticles = (params = {}) => {
+    return axios.get('/trash/articles', { params });
+};
+
+// 从回收站恢复文章
+export const restoreArticle = (id) => {
+    return axios.post(`/articles/${id}/restore`);
+};
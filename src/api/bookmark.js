@@ -0,0 +1,16 @@
+import axios from 'axios';
+
+// 收藏
+export const getBookmarks = (params = {}) => axios.get('/users/me/bookmarks', { params });
+export const addBookmark = (articleId) => axios.put(`/articles/${articleId}/bookmark`);
+export const removeBookmark = (articleId) => axios.delete(`/articles/${articleId}/bookmark`);
+
+// 阅读列表
+export const getReadingLists = () => axios.get('/users/me/reading-lists');
+export const getReadingList = (id) => axios.get(`/users/me/reading-lists/${id}`);
+export const createReadingList = (data) => axios.post('/users/me/reading-lists', data);
+export const updateReadingList = (id, data) => axios.put(`/users/me/reading-lists/${id}`, data);
+export const deleteReadingList = (id) => axios.delete(`/users/me/reading-lists/${id}`);
+export const addToReadingList = (id, articleId) => axios.put(`/users/me/reading-lists/${id}/articles/${articleId}`);
+export const removeFromReadingList = (id, articleId) => axios.delete(`/users/me/reading-lists/${id}/articles/${articleId}`);
+export const reorderReadingList = (id, articleIds) => axios.put(`/users/me/reading-lists/${id}/order`, { article_ids: articleIds });
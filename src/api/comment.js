@@ -6,4 +6,10 @@ export const getCommentsByArticleId = (articleId) => axios.get(`/articles/${arti
 // 评论操作
 export const createComment = (articleId, data) => axios.post(`/articles/${articleId}/comments`, data);
 export const updateComment = (commentId, data) => axios.put(`/comments/${commentId}`, data);
-export const deleteComment = (commentId) => axios.delete(`/comments/${commentId}`);
\ No newline at end of file
+export const deleteComment = (commentId) => axios.delete(`/comments/${commentId}`);
+// 切换对评论的反应
+export const toggleCommentReaction = (commentId, reaction) => axios.post(`/comments/${commentId}/reactions`, { reaction });
+
+// 回收站
+export const getTrashedComments = (params = {}) => axios.get('/trash/comments', { params });
+export const restoreComment = (commentId) => axios.post(`/comments/${commentId}/restore`);
@@ -0,0 +1,16 @@
+import axios from 'axios';
+
+// 订阅实时事件：新文章公告、指定文章的评论（articleId）以及登录用户的站内通知
+// handlers 的键为事件类型，如 { 'comment.created': (data) => {} }
+export const subscribeEvents = ({ articleId, token } = {}, handlers = {}) => {
+    const params = new URLSearchParams();
+    if (articleId) params.set('article', articleId);
+    if (token) params.set('access_token', token);
+    const query = params.toString();
+
+    const source = new EventSource(`${axios.defaults.baseURL}/events${query ? `?${query}` : ''}`);
+    Object.entries(handlers).forEach(([type, handler]) => {
+        source.addEventListener(type, (e) => handler(JSON.parse(e.data)));
+    });
+    return source;
+};
@@ -0,0 +1,10 @@
+import axios from 'axios';
+
+// 关注
+export const followUser = (userId) => axios.put(`/users/${userId}/follow`);
+export const unfollowUser = (userId) => axios.delete(`/users/${userId}/follow`);
+export const followCategory = (categoryId) => axios.put(`/categories/${categoryId}/follow`);
+export const unfollowCategory = (categoryId) => axios.delete(`/categories/${categoryId}/follow`);
+
+// 动态，cursor 为上一页返回的 next_cursor
+export const getFeed = (params = {}) => axios.get('/feed', { params });
@@ -7,14 +7,27 @@ export const login = (data) => axios.post('/login', data);
 // 用户信息管理
 export const getUserInfo = () => axios.get('/users/me');
 export const updateUserInfo = (userId, data) => axios.put(`/users/${userId}`, data);
+export const updateProfile = (data) => axios.put('/users/me/profile', data);
+
+// 作者公开主页
+export const getAuthor = (username) => axios.get(`/authors/${encodeURIComponent(username)}`);
+export const getAuthorArticles = (username, params = {}) => axios.get(`/authors/${encodeURIComponent(username)}/articles`, { params });
 
 // 用户统计
 export const getArticleCount = () => axios.get('/users/me/articles/count');
 
 // 管理员功能
-export const getAllUsers = () => axios.get('/users');
+export const getAllUsers = (params = {}) => axios.get('/users', { params });
 export const deleteUser = (userId) => axios.delete(`/users/${userId}`);
 export const updateUserRole = (userId, role) => axios.put(`/users/${userId}/role`, { role });
-export const changePassword = (userId, params) => {
-    return axios.post(`/api/change-password/${userId}`, params);
-};
\ No newline at end of file
+export const updateUserStatus = (userId, data) => axios.put(`/users/${userId}/status`, data);
+export const changePassword = (params) => axios.put('/users/me/password', {
+    old_password: params.oldPassword,
+    new_password: params.newPassword
+});
+
+// 邮箱验证与找回密码
+export const verifyEmail = (token) => axios.post('/email/verify', { token });
+export const resendVerification = () => axios.post('/users/me/email-verification');
+export const forgotPassword = (email) => axios.post('/password/forgot', { email });
+export const resetPassword = (token, password) => axios.post('/password/reset', { token, password });
\ No newline at end of file
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage 本地文件系统存储，适用于单实例部署
+type LocalStorage struct {
+	dir        string
+	urlPrefix  string
+	signingKey []byte
+}
+
+// NewLocalStorage 创建本地存储，文件保存在 dir 下，通过 urlPrefix 对外访问
+func NewLocalStorage(dir, urlPrefix, signingKey string) *LocalStorage {
+	return &LocalStorage{
+		dir:        dir,
+		urlPrefix:  urlPrefix,
+		signingKey: []byte(signingKey),
+	}
+}
+
+// Dir 返回存储根目录
+func (s *LocalStorage) Dir() string {
+	return s.dir
+}
+
+func (s *LocalStorage) filePath(key string) (string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(cleaned)), nil
+}
+
+// Put 写入文件，先写临时文件再重命名，避免读到半截文件
+func (s *LocalStorage) Put(key string, r io.Reader, contentType string) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
+
+// Get 读取文件
+func (s *LocalStorage) Get(key string) (io.ReadCloser, error) {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// Stat 获取文件信息
+func (s *LocalStorage) Stat(key string) (*Object, error) {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(filePath)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && info.IsDir()) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Object{Key: key, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// Delete 删除文件
+func (s *LocalStorage) Delete(key string) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List 遍历 prefix 下的所有文件，跳过写入中的临时文件
+func (s *LocalStorage) List(prefix string, fn func(Object) error) error {
+	err := filepath.WalkDir(s.dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, filePath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		return fn(Object{Key: key, Size: info.Size(), ModTime: info.ModTime()})
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// SignedURL 生成带过期时间和HMAC签名的访问地址
+func (s *LocalStorage) SignedURL(key string, expires time.Duration) (string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	expiresAt := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+
+	q := url.Values{}
+	q.Set("expires", expiresAt)
+	q.Set("signature", s.sign(cleaned, expiresAt))
+	return s.urlPrefix + cleaned + "?" + q.Encode(), nil
+}
+
+// VerifySignedURL 校验 SignedURL 生成的签名参数
+func (s *LocalStorage) VerifySignedURL(key string, query url.Values) bool {
+	expiresAt := query.Get("expires")
+	unix, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return false
+	}
+	expected := s.sign(key, expiresAt)
+	return hmac.Equal([]byte(expected), []byte(query.Get("signature")))
+}
+
+func (s *LocalStorage) sign(key, expiresAt string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key + "\n" + expiresAt))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStorageVerifySignedURL(t *testing.T) {
+	s := NewLocalStorage(t.TempDir(), "/uploads/", "secret")
+
+	signed, err := s.SignedURL("ab/abc_large.jpg", time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("parse %q: %v", signed, err)
+	}
+	if u.Path != "/uploads/ab/abc_large.jpg" {
+		t.Fatalf("path = %q", u.Path)
+	}
+	valid := u.Query()
+
+	expired := url.Values{}
+	expiredAt := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	expired.Set("expires", expiredAt)
+	expired.Set("signature", s.sign("ab/abc_large.jpg", expiredAt))
+
+	tampered := url.Values{}
+	tampered.Set("expires", valid.Get("expires"))
+	tampered.Set("signature", strings.Repeat("0", len(valid.Get("signature"))))
+
+	tests := []struct {
+		name  string
+		key   string
+		query url.Values
+		want  bool
+	}{
+		{"valid", "ab/abc_large.jpg", valid, true},
+		{"other key", "ab/abd_large.jpg", valid, false},
+		{"expired", "ab/abc_large.jpg", expired, false},
+		{"tampered signature", "ab/abc_large.jpg", tampered, false},
+		{"missing expires", "ab/abc_large.jpg", url.Values{"signature": valid["signature"]}, false},
+		{"no params", "ab/abc_large.jpg", url.Values{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.VerifySignedURL(tt.key, tt.query); got != tt.want {
+				t.Errorf("VerifySignedURL = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	other := NewLocalStorage(t.TempDir(), "/uploads/", "other-secret")
+	if other.VerifySignedURL("ab/abc_large.jpg", valid) {
+		t.Error("signature accepted with a different signing key")
+	}
+}
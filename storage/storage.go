@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("文件不存在")
+	ErrInvalidKey = errors.New("无效的文件路径")
+)
+
+// Storage 上传文件存储后端，key 为相对路径（如 ab/abcdef_large.jpg）
+type Storage interface {
+	// Put 写入文件，已存在时覆盖
+	Put(key string, r io.Reader, contentType string) error
+	// Get 读取文件，不存在时返回 ErrNotFound
+	Get(key string) (io.ReadCloser, error)
+	// Stat 获取文件信息，不存在时返回 ErrNotFound
+	Stat(key string) (*Object, error)
+	// Delete 删除文件，不存在时不报错
+	Delete(key string) error
+	// List 遍历 prefix 下的所有文件
+	List(prefix string, fn func(Object) error) error
+	// SignedURL 生成限时有效的访问地址
+	SignedURL(key string, expires time.Duration) (string, error)
+}
+
+// Object 存储中的文件信息
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Config 存储后端配置
+type Config struct {
+	Backend string // local 或 s3
+
+	// 本地存储
+	LocalDir   string
+	URLPrefix  string
+	SigningKey string
+
+	// S3兼容存储（AWS S3、MinIO 等）
+	S3 S3Config
+}
+
+// New 根据配置创建存储后端
+func New(cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case "", "local":
+		return NewLocalStorage(cfg.LocalDir, cfg.URLPrefix, cfg.SigningKey), nil
+	case "s3":
+		return NewS3Storage(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", cfg.Backend)
+	}
+}
+
+// CleanKey 规范化文件路径，拒绝绝对路径和越界的 ..
+func CleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
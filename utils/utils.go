@@ -2,24 +2,72 @@ package utils
 
 import (
 	"encoding/json"
+	"my_blog/apperrors"
+	"my_blog/i18n"
+	"my_blog/logging"
 	"my_blog/models"
 	"net/http"
 )
 
-// SendResponse 发送统一格式的JSON响应
-func SendResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(models.APIResponse{
-		Code:    statusCode,
-		Message: message,
-		Data:    data,
-	})
+// SendResponse 发送统一格式的JSON响应，message 为 i18n 消息码，按请求语言本地化；
+// 状态码不小于400时同时作为 error_code 返回
+func SendResponse(w http.ResponseWriter, r *http.Request, statusCode int, code string, data interface{}) {
+	writeJSON(w, r, statusCode, code, data, nil)
 }
 
 // SendErrorResponse 发送错误响应
-func SendErrorResponse(w http.ResponseWriter, statusCode int, message string) {
-	SendResponse(w, statusCode, message, nil)
+func SendErrorResponse(w http.ResponseWriter, r *http.Request, statusCode int, code string) {
+	SendResponse(w, r, statusCode, code, nil)
+}
+
+// SendValidationError 发送字段校验失败响应，errors 列出每个不合法字段及原因
+func SendValidationError(w http.ResponseWriter, r *http.Request, errors []models.FieldError) {
+	writeJSON(w, r, http.StatusBadRequest, i18n.MsgValidationFailed, nil, errors)
+}
+
+// errorStatus 领域错误类别对应的HTTP状态码
+var errorStatus = map[apperrors.Kind]int{
+	apperrors.KindNotFound:     http.StatusNotFound,
+	apperrors.KindConflict:     http.StatusConflict,
+	apperrors.KindValidation:   http.StatusBadRequest,
+	apperrors.KindForbidden:    http.StatusForbidden,
+	apperrors.KindUnauthorized: http.StatusUnauthorized,
+}
+
+// SendError 将服务层错误转换为响应：领域错误按类别返回对应状态码和消息，
+// 其他错误连同请求ID记录日志后返回500和 fallback 提示，不向客户端暴露内部细节
+func SendError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	appErr := apperrors.As(err)
+	if appErr == nil || appErr.Kind == apperrors.KindInternal {
+		logging.FromContext(r.Context()).Error("request failed", "code", fallback, "error", err)
+		SendErrorResponse(w, r, http.StatusInternalServerError, fallback)
+		return
+	}
+
+	writeJSON(w, r, errorStatus[appErr.Kind], appErr.Code, nil, appErr.Fields)
+}
+
+// writeJSON 按请求语言本地化消息和字段错误后写出响应
+func writeJSON(w http.ResponseWriter, r *http.Request, statusCode int, code string, data interface{}, fields []models.FieldError) {
+	locale := i18n.FromContext(r.Context())
+
+	resp := models.APIResponse{
+		Code:    statusCode,
+		Message: i18n.T(locale, code),
+		Data:    data,
+	}
+	if statusCode >= http.StatusBadRequest {
+		resp.ErrorCode = code
+	}
+	for _, f := range fields {
+		f.Reason = i18n.T(locale, f.Code, f.Params...)
+		resp.Errors = append(resp.Errors, f)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", string(locale))
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(resp)
 }
 
 // Constants 定义常量
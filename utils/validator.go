@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"fmt"
+	"my_blog/i18n"
+	"my_blog/models"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	emailPattern    = regexp.MustCompile(`^[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}$`)
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_\p{Han}]{3,32}$`)
+)
+
+// Validate 按结构体字段的 validate 标签校验请求数据，返回所有不合法字段，全部合法时返回nil。
+//
+// 支持的规则（逗号分隔，按顺序检查，每个字段只报告第一个失败的规则）：
+//
+//	required       不能为空
+//	omitempty      为空时跳过其余规则
+//	min=N / max=N  字符串按字符数，数字按数值
+//	maxbytes=N     字符串的 UTF-8 字节数，用于按字节计算容量的列（如 TEXT）
+//	email          邮箱格式
+//	username       3-32位字母、数字、下划线或汉字
+//	password       至少8位，同时包含字母和数字
+//	oneof=a b c    取值必须为列出的值之一
+//	url            http 或 https 绝对地址
+//
+// 字段名取自 json 标签，失败原因以 i18n 消息码返回，由响应时按请求语言本地化。
+// 部分更新的字段（models.Optional）未出现在请求中时不校验，显式为 null 时按空值校验
+func Validate(v interface{}) []models.FieldError {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	rt := rv.Type()
+
+	var errs []models.FieldError
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		rules := field.Tag.Get("validate")
+		if rules == "" {
+			continue
+		}
+		value := rv.Field(i)
+		if opt, ok := asOptional(value); ok {
+			if !opt.Present() {
+				continue
+			}
+			value = reflect.ValueOf(opt.Current())
+		}
+		if code, params := checkRules(value, rules); code != "" {
+			errs = append(errs, models.FieldError{Field: fieldName(field), Code: code, Params: params})
+		}
+	}
+	return errs
+}
+
+// optional 部分更新中可能未出现的字段，见 models.Optional
+type optional interface {
+	Present() bool
+	Current() interface{}
+}
+
+// asOptional 判断字段是否为部分更新的可选字段
+func asOptional(value reflect.Value) (optional, bool) {
+	if !value.CanInterface() {
+		return nil, false
+	}
+	opt, ok := value.Interface().(optional)
+	return opt, ok
+}
+
+// fieldName 返回字段在请求中的名称（json标签名，缺省为字段名）
+func fieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// checkRules 依次检查规则，返回第一个失败规则的消息码及参数
+func checkRules(value reflect.Value, rules string) (string, []interface{}) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value = reflect.Value{}
+			break
+		}
+		value = value.Elem()
+	}
+	empty := !value.IsValid() || value.IsZero()
+
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "omitempty":
+			if empty {
+				return "", nil
+			}
+		case "required":
+			if empty || (value.Kind() == reflect.String && strings.TrimSpace(value.String()) == "") {
+				return i18n.MsgFieldRequired, nil
+			}
+		case "min", "max":
+			if code, params := checkLength(value, name, param); code != "" {
+				return code, params
+			}
+		case "maxbytes":
+			if limit, err := strconv.Atoi(param); err == nil && value.Kind() == reflect.String && len(value.String()) > limit {
+				return i18n.MsgFieldMaxBytes, []interface{}{limit}
+			}
+		case "email":
+			if !emailPattern.MatchString(value.String()) {
+				return i18n.MsgFieldEmail, nil
+			}
+		case "username":
+			if !usernamePattern.MatchString(value.String()) {
+				return i18n.MsgFieldUsername, nil
+			}
+		case "password":
+			if !isStrongPassword(value.String()) {
+				return i18n.MsgFieldPassword, nil
+			}
+		case "url":
+			if !isHTTPURL(value.String()) {
+				return i18n.MsgFieldURL, nil
+			}
+		case "oneof":
+			if !isOneOf(value, strings.Fields(param)) {
+				return i18n.MsgFieldOneOf, []interface{}{strings.Join(strings.Fields(param), ", ")}
+			}
+		}
+	}
+	return "", nil
+}
+
+// checkLength 校验字符串长度或数值范围
+func checkLength(value reflect.Value, rule, param string) (string, []interface{}) {
+	if !value.IsValid() {
+		return "", nil
+	}
+	limit, err := strconv.Atoi(param)
+	if err != nil {
+		return "", nil
+	}
+	params := []interface{}{limit}
+
+	switch value.Kind() {
+	case reflect.String:
+		n := utf8.RuneCountInString(value.String())
+		if rule == "min" && n < limit {
+			return i18n.MsgFieldMinLength, params
+		}
+		if rule == "max" && n > limit {
+			return i18n.MsgFieldMaxLength, params
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := value.Int()
+		if rule == "min" && n < int64(limit) {
+			return i18n.MsgFieldMin, params
+		}
+		if rule == "max" && n > int64(limit) {
+			return i18n.MsgFieldMax, params
+		}
+	}
+	return "", nil
+}
+
+// isStrongPassword 密码至少8位，且同时包含字母和数字
+func isStrongPassword(password string) bool {
+	if utf8.RuneCountInString(password) < 8 {
+		return false
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	return hasLetter && hasDigit
+}
+
+// isOneOf 判断值是否在允许列表中
+func isOneOf(value reflect.Value, options []string) bool {
+	if !value.IsValid() {
+		return false
+	}
+	actual := fmt.Sprint(value.Interface())
+	for _, option := range options {
+		if actual == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isHTTPURL 判断是否为带主机名的 http 或 https 地址
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
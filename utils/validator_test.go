@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"encoding/json"
+	"my_blog/i18n"
+	"my_blog/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type validateRequest struct {
+	Username string  `json:"username" validate:"required,username"`
+	Email    string  `json:"email" validate:"omitempty,email,max=20"`
+	Password string  `json:"password" validate:"required,password"`
+	Website  string  `json:"website" validate:"omitempty,url"`
+	Role     int     `json:"role" validate:"oneof=1 2 3"`
+	Age      int     `json:"age" validate:"omitempty,min=18,max=120"`
+	Nickname *string `json:"nickname,omitempty" validate:"omitempty,min=2"`
+	Content  string  `json:"content" validate:"maxbytes=6"`
+	Plain    string  `validate:"max=3"`
+	Ignored  string  `json:"ignored"`
+}
+
+func validRequest() validateRequest {
+	return validateRequest{Username: "张三_01", Password: "secret123", Role: 2}
+}
+
+// fieldCodes 将校验结果转换为 字段 -> 消息码
+func fieldCodes(errs []models.FieldError) map[string]string {
+	codes := map[string]string{}
+	for _, e := range errs {
+		codes[e.Field] = e.Code
+	}
+	return codes
+}
+
+func TestValidate(t *testing.T) {
+	short := "a"
+	tests := []struct {
+		name   string
+		modify func(*validateRequest)
+		want   map[string]string
+	}{
+		{"valid", func(*validateRequest) {}, map[string]string{}},
+		{"required empty", func(r *validateRequest) { r.Username = "" }, map[string]string{"username": i18n.MsgFieldRequired}},
+		{"required whitespace", func(r *validateRequest) { r.Username = "   " }, map[string]string{"username": i18n.MsgFieldRequired}},
+		{"username too short", func(r *validateRequest) { r.Username = "ab" }, map[string]string{"username": i18n.MsgFieldUsername}},
+		{"username symbols", func(r *validateRequest) { r.Username = "bad-name" }, map[string]string{"username": i18n.MsgFieldUsername}},
+		{"omitempty skips email", func(r *validateRequest) { r.Email = "" }, map[string]string{}},
+		{"email format", func(r *validateRequest) { r.Email = "not-an-email" }, map[string]string{"email": i18n.MsgFieldEmail}},
+		{"first failing rule only", func(r *validateRequest) { r.Email = "a-very-long-address@example.com" }, map[string]string{"email": i18n.MsgFieldMaxLength}},
+		{"password without digit", func(r *validateRequest) { r.Password = "onlyletters" }, map[string]string{"password": i18n.MsgFieldPassword}},
+		{"password too short", func(r *validateRequest) { r.Password = "abc123" }, map[string]string{"password": i18n.MsgFieldPassword}},
+		{"url scheme", func(r *validateRequest) { r.Website = "ftp://example.com" }, map[string]string{"website": i18n.MsgFieldURL}},
+		{"url without host", func(r *validateRequest) { r.Website = "https://" }, map[string]string{"website": i18n.MsgFieldURL}},
+		{"url ok", func(r *validateRequest) { r.Website = "https://example.com/a" }, map[string]string{}},
+		{"oneof", func(r *validateRequest) { r.Role = 4 }, map[string]string{"role": i18n.MsgFieldOneOf}},
+		{"number min", func(r *validateRequest) { r.Age = 17 }, map[string]string{"age": i18n.MsgFieldMin}},
+		{"number max", func(r *validateRequest) { r.Age = 121 }, map[string]string{"age": i18n.MsgFieldMax}},
+		{"nil pointer omitted", func(r *validateRequest) { r.Nickname = nil }, map[string]string{}},
+		{"pointer checked", func(r *validateRequest) { r.Nickname = &short }, map[string]string{"nickname": i18n.MsgFieldMinLength}},
+		// 3个汉字为3个字符、9个字节
+		{"max counts runes", func(r *validateRequest) { r.Plain = "一二三" }, map[string]string{}},
+		{"maxbytes counts bytes", func(r *validateRequest) { r.Content = "一二三" }, map[string]string{"content": i18n.MsgFieldMaxBytes}},
+		{"maxbytes ok", func(r *validateRequest) { r.Content = "一二" }, map[string]string{}},
+		{"field name without json tag", func(r *validateRequest) { r.Plain = "abcd" }, map[string]string{"Plain": i18n.MsgFieldMaxLength}},
+		{"multiple fields", func(r *validateRequest) { r.Username = ""; r.Role = 0 }, map[string]string{
+			"username": i18n.MsgFieldRequired,
+			"role":     i18n.MsgFieldOneOf,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validRequest()
+			tt.modify(&req)
+			if got := fieldCodes(Validate(&req)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	req := validRequest()
+	req.Role = 5
+	req.Age = 10
+	errs := Validate(&req)
+	params := map[string][]interface{}{}
+	for _, e := range errs {
+		params[e.Field] = e.Params
+	}
+	if got := params["role"]; !reflect.DeepEqual(got, []interface{}{"1, 2, 3"}) {
+		t.Errorf("oneof params = %v", got)
+	}
+	if got := params["age"]; !reflect.DeepEqual(got, []interface{}{18}) {
+		t.Errorf("min params = %v", got)
+	}
+}
+
+type patchRequest struct {
+	Title   models.Optional[string] `json:"title" validate:"required,max=5"`
+	Bio     models.Optional[string] `json:"bio" validate:"max=3"`
+	Website models.Optional[string] `json:"website" validate:"omitempty,url"`
+}
+
+func TestValidateOptional(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want map[string]string
+	}{
+		{"absent fields skipped", `{}`, map[string]string{}},
+		{"present and valid", `{"title":"标题","bio":"abc","website":"https://example.com"}`, map[string]string{}},
+		{"required null", `{"title":null}`, map[string]string{"title": i18n.MsgFieldRequired}},
+		{"required empty", `{"title":""}`, map[string]string{"title": i18n.MsgFieldRequired}},
+		{"present too long", `{"title":"一二三四五六"}`, map[string]string{"title": i18n.MsgFieldMaxLength}},
+		{"null without required", `{"bio":null}`, map[string]string{}},
+		{"omitempty null", `{"website":null}`, map[string]string{}},
+		{"omitempty invalid", `{"website":"example"}`, map[string]string{"website": i18n.MsgFieldURL}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req patchRequest
+			if err := json.NewDecoder(strings.NewReader(tt.body)).Decode(&req); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got := fieldCodes(Validate(&req)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNonStruct(t *testing.T) {
+	if errs := Validate("text"); errs != nil {
+		t.Errorf("Validate(string) = %v, want nil", errs)
+	}
+	if errs := Validate(&validateRequest{Username: "张三_01", Password: "secret123", Role: 1}); errs != nil {
+		t.Errorf("Validate(valid) = %v, want nil", errs)
+	}
+}